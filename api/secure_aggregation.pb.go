@@ -20,15 +20,115 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Scheme selects how the participants split their inputs
+type Scheme int32
+
+const (
+	Scheme_ADDITIVE Scheme = 0 // n-out-of-n additive sharing
+	Scheme_SHAMIR   Scheme = 1 // t-out-of-n Shamir sharing, tolerates dropouts
+)
+
+// Enum value maps for Scheme.
+var (
+	Scheme_name = map[int32]string{
+		0: "ADDITIVE",
+		1: "SHAMIR",
+	}
+	Scheme_value = map[string]int32{
+		"ADDITIVE": 0,
+		"SHAMIR":   1,
+	}
+)
+
+func (x Scheme) Enum() *Scheme {
+	p := new(Scheme)
+	*p = x
+	return p
+}
+
+func (x Scheme) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Scheme) Descriptor() protoreflect.EnumDescriptor {
+	return file_secure_aggregation_proto_enumTypes[0].Descriptor()
+}
+
+func (Scheme) Type() protoreflect.EnumType {
+	return &file_secure_aggregation_proto_enumTypes[0]
+}
+
+func (x Scheme) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Scheme.Descriptor instead.
+func (Scheme) EnumDescriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{0}
+}
+
+// Mechanism selects the differential privacy noise participants add to the
+// output
+type Mechanism int32
+
+const (
+	Mechanism_NO_NOISE Mechanism = 0
+	Mechanism_LAPLACE  Mechanism = 1 // (epsilon, 0)-DP
+	Mechanism_GAUSSIAN Mechanism = 2 // (epsilon, delta)-DP
+)
+
+// Enum value maps for Mechanism.
+var (
+	Mechanism_name = map[int32]string{
+		0: "NO_NOISE",
+		1: "LAPLACE",
+		2: "GAUSSIAN",
+	}
+	Mechanism_value = map[string]int32{
+		"NO_NOISE": 0,
+		"LAPLACE":  1,
+		"GAUSSIAN": 2,
+	}
+)
+
+func (x Mechanism) Enum() *Mechanism {
+	p := new(Mechanism)
+	*p = x
+	return p
+}
+
+func (x Mechanism) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Mechanism) Descriptor() protoreflect.EnumDescriptor {
+	return file_secure_aggregation_proto_enumTypes[1].Descriptor()
+}
+
+func (Mechanism) Type() protoreflect.EnumType {
+	return &file_secure_aggregation_proto_enumTypes[1]
+}
+
+func (x Mechanism) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Mechanism.Descriptor instead.
+func (Mechanism) EnumDescriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{1}
+}
+
 // Share message represents a part of the secret and the sender's identity
 type Share struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Part int64  `protobuf:"varint,1,opt,name=part,proto3" json:"part,omitempty"` // The part of the secret being sent
-	From string `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`  // Identifier for the sender
-	To   string `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`      // Indentifier recivier
+	Parts   [][]byte `protobuf:"bytes,7,rep,name=parts,proto3" json:"parts,omitempty"`     // The parts of the secret being sent, one field element per vector element
+	From    string   `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`       // Identifier for the sender
+	To      string   `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`           // Indentifier recivier
+	Session string   `protobuf:"bytes,4,opt,name=session,proto3" json:"session,omitempty"` // Session the share belongs to
+	Seq     uint64   `protobuf:"varint,5,opt,name=seq,proto3" json:"seq,omitempty"`        // Sequence number, unique per sender within the session
 }
 
 func (x *Share) Reset() {
@@ -63,11 +163,11 @@ func (*Share) Descriptor() ([]byte, []int) {
 	return file_secure_aggregation_proto_rawDescGZIP(), []int{0}
 }
 
-func (x *Share) GetPart() int64 {
+func (x *Share) GetParts() [][]byte {
 	if x != nil {
-		return x.Part
+		return x.Parts
 	}
-	return 0
+	return nil
 }
 
 func (x *Share) GetFrom() string {
@@ -84,14 +184,30 @@ func (x *Share) GetTo() string {
 	return ""
 }
 
+func (x *Share) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+func (x *Share) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
 type ShareOut struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	From string `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
-	To   string `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
-	Data int64  `protobuf:"varint,3,opt,name=data,proto3" json:"data,omitempty"`
+	From    string   `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To      string   `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Data    [][]byte `protobuf:"bytes,7,rep,name=data,proto3" json:"data,omitempty"` // One field element per vector element
+	Session string   `protobuf:"bytes,4,opt,name=session,proto3" json:"session,omitempty"`
+	Seq     uint64   `protobuf:"varint,5,opt,name=seq,proto3" json:"seq,omitempty"` // Sequence number, unique per sender within the session
 }
 
 func (x *ShareOut) Reset() {
@@ -140,10 +256,24 @@ func (x *ShareOut) GetTo() string {
 	return ""
 }
 
-func (x *ShareOut) GetData() int64 {
+func (x *ShareOut) GetData() [][]byte {
 	if x != nil {
 		return x.Data
 	}
+	return nil
+}
+
+func (x *ShareOut) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+func (x *ShareOut) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
 	return 0
 }
 
@@ -200,6 +330,7 @@ type GetAddedSharesRequest struct {
 	unknownFields protoimpl.UnknownFields
 
 	Participant string `protobuf:"bytes,1,opt,name=participant,proto3" json:"participant,omitempty"`
+	Session     string `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`
 }
 
 func (x *GetAddedSharesRequest) Reset() {
@@ -241,12 +372,19 @@ func (x *GetAddedSharesRequest) GetParticipant() string {
 	return ""
 }
 
+func (x *GetAddedSharesRequest) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
 type GetAddedSharesResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	AddedShares int64 `protobuf:"varint,1,opt,name=addedShares,proto3" json:"addedShares,omitempty"`
+	AddedShares [][]byte `protobuf:"bytes,3,rep,name=addedShares,proto3" json:"addedShares,omitempty"` // Element-wise sum
 }
 
 func (x *GetAddedSharesResponse) Reset() {
@@ -281,11 +419,11 @@ func (*GetAddedSharesResponse) Descriptor() ([]byte, []int) {
 	return file_secure_aggregation_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *GetAddedSharesResponse) GetAddedShares() int64 {
+func (x *GetAddedSharesResponse) GetAddedShares() [][]byte {
 	if x != nil {
 		return x.AddedShares
 	}
-	return 0
+	return nil
 }
 
 type GetAddedOutRequest struct {
@@ -294,6 +432,7 @@ type GetAddedOutRequest struct {
 	unknownFields protoimpl.UnknownFields
 
 	Participant string `protobuf:"bytes,1,opt,name=participant,proto3" json:"participant,omitempty"`
+	Session     string `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`
 }
 
 func (x *GetAddedOutRequest) Reset() {
@@ -335,12 +474,19 @@ func (x *GetAddedOutRequest) GetParticipant() string {
 	return ""
 }
 
+func (x *GetAddedOutRequest) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
 type GetAddedOutResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	AddedOut int64 `protobuf:"varint,1,opt,name=addedOut,proto3" json:"addedOut,omitempty"`
+	AddedOut [][]byte `protobuf:"bytes,3,rep,name=addedOut,proto3" json:"addedOut,omitempty"` // Element-wise sum
 }
 
 func (x *GetAddedOutResponse) Reset() {
@@ -375,134 +521,1637 @@ func (*GetAddedOutResponse) Descriptor() ([]byte, []int) {
 	return file_secure_aggregation_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *GetAddedOutResponse) GetAddedOut() int64 {
+func (x *GetAddedOutResponse) GetAddedOut() [][]byte {
 	if x != nil {
 		return x.AddedOut
 	}
+	return nil
+}
+
+type GetOutSharesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Participant string `protobuf:"bytes,1,opt,name=participant,proto3" json:"participant,omitempty"`
+	Session     string `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`
+	Minimum     int32  `protobuf:"varint,3,opt,name=minimum,proto3" json:"minimum,omitempty"` // Number of out shares to wait for
+}
+
+func (x *GetOutSharesRequest) Reset() {
+	*x = GetOutSharesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOutSharesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutSharesRequest) ProtoMessage() {}
+
+func (x *GetOutSharesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutSharesRequest.ProtoReflect.Descriptor instead.
+func (*GetOutSharesRequest) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetOutSharesRequest) GetParticipant() string {
+	if x != nil {
+		return x.Participant
+	}
+	return ""
+}
+
+func (x *GetOutSharesRequest) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+func (x *GetOutSharesRequest) GetMinimum() int32 {
+	if x != nil {
+		return x.Minimum
+	}
 	return 0
 }
 
-var File_secure_aggregation_proto protoreflect.FileDescriptor
+type GetOutSharesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_secure_aggregation_proto_rawDesc = []byte{
-	0x0a, 0x18, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x5f, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x3f, 0x0a, 0x05, 0x53, 0x68,
-	0x61, 0x72, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x04, 0x70, 0x61, 0x72, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74,
-	0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x74, 0x6f, 0x22, 0x42, 0x0a, 0x08, 0x53,
-	0x68, 0x61, 0x72, 0x65, 0x4f, 0x75, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74,
-	0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x64,
-	0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22,
-	0x1f, 0x0a, 0x03, 0x41, 0x63, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x22, 0x39, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x65, 0x64, 0x53, 0x68, 0x61, 0x72,
-	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72,
-	0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
-	0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x22, 0x3a, 0x0a, 0x16, 0x47,
-	0x65, 0x74, 0x41, 0x64, 0x64, 0x65, 0x64, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x61, 0x64, 0x64, 0x65, 0x64, 0x53, 0x68,
-	0x61, 0x72, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x61, 0x64, 0x64, 0x65,
-	0x64, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x22, 0x36, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x41, 0x64,
-	0x64, 0x65, 0x64, 0x4f, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a,
-	0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x22,
-	0x31, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x65, 0x64, 0x4f, 0x75, 0x74, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x64, 0x64, 0x65, 0x64, 0x4f,
-	0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x61, 0x64, 0x64, 0x65, 0x64, 0x4f,
-	0x75, 0x74, 0x32, 0xd1, 0x01, 0x0a, 0x14, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x53, 0x68, 0x61,
-	0x72, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x53,
-	0x65, 0x6e, 0x64, 0x53, 0x68, 0x61, 0x72, 0x65, 0x12, 0x06, 0x2e, 0x53, 0x68, 0x61, 0x72, 0x65,
-	0x1a, 0x04, 0x2e, 0x41, 0x63, 0x6b, 0x22, 0x00, 0x12, 0x1f, 0x0a, 0x0c, 0x53, 0x65, 0x6e, 0x64,
-	0x53, 0x68, 0x61, 0x72, 0x65, 0x4f, 0x75, 0x74, 0x12, 0x09, 0x2e, 0x53, 0x68, 0x61, 0x72, 0x65,
-	0x4f, 0x75, 0x74, 0x1a, 0x04, 0x2e, 0x41, 0x63, 0x6b, 0x12, 0x41, 0x0a, 0x0e, 0x47, 0x65, 0x74,
-	0x41, 0x64, 0x64, 0x65, 0x64, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x12, 0x16, 0x2e, 0x47, 0x65,
-	0x74, 0x41, 0x64, 0x64, 0x65, 0x64, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x65, 0x64, 0x53, 0x68,
-	0x61, 0x72, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x0b,
-	0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x65, 0x64, 0x4f, 0x75, 0x74, 0x12, 0x13, 0x2e, 0x47, 0x65,
-	0x74, 0x41, 0x64, 0x64, 0x65, 0x64, 0x4f, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x14, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x65, 0x64, 0x4f, 0x75, 0x74, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x04, 0x5a, 0x02, 0x2e, 0x2f, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	Shares []*ShareOut `protobuf:"bytes,1,rep,name=shares,proto3" json:"shares,omitempty"`
 }
 
-var (
-	file_secure_aggregation_proto_rawDescOnce sync.Once
-	file_secure_aggregation_proto_rawDescData = file_secure_aggregation_proto_rawDesc
-)
+func (x *GetOutSharesResponse) Reset() {
+	*x = GetOutSharesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_secure_aggregation_proto_rawDescGZIP() []byte {
-	file_secure_aggregation_proto_rawDescOnce.Do(func() {
-		file_secure_aggregation_proto_rawDescData = protoimpl.X.CompressGZIP(file_secure_aggregation_proto_rawDescData)
-	})
-	return file_secure_aggregation_proto_rawDescData
+func (x *GetOutSharesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_secure_aggregation_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
-var file_secure_aggregation_proto_goTypes = []interface{}{
-	(*Share)(nil),                  // 0: Share
-	(*ShareOut)(nil),               // 1: ShareOut
-	(*Ack)(nil),                    // 2: Ack
-	(*GetAddedSharesRequest)(nil),  // 3: GetAddedSharesRequest
-	(*GetAddedSharesResponse)(nil), // 4: GetAddedSharesResponse
-	(*GetAddedOutRequest)(nil),     // 5: GetAddedOutRequest
-	(*GetAddedOutResponse)(nil),    // 6: GetAddedOutResponse
+func (*GetOutSharesResponse) ProtoMessage() {}
+
+func (x *GetOutSharesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_secure_aggregation_proto_depIdxs = []int32{
-	0, // 0: SecretSharingService.SendShare:input_type -> Share
-	1, // 1: SecretSharingService.SendShareOut:input_type -> ShareOut
-	3, // 2: SecretSharingService.GetAddedShares:input_type -> GetAddedSharesRequest
-	5, // 3: SecretSharingService.GetAddedOut:input_type -> GetAddedOutRequest
-	2, // 4: SecretSharingService.SendShare:output_type -> Ack
-	2, // 5: SecretSharingService.SendShareOut:output_type -> Ack
-	4, // 6: SecretSharingService.GetAddedShares:output_type -> GetAddedSharesResponse
-	6, // 7: SecretSharingService.GetAddedOut:output_type -> GetAddedOutResponse
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+
+// Deprecated: Use GetOutSharesResponse.ProtoReflect.Descriptor instead.
+func (*GetOutSharesResponse) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{8}
 }
 
-func init() { file_secure_aggregation_proto_init() }
-func file_secure_aggregation_proto_init() {
-	if File_secure_aggregation_proto != nil {
-		return
+func (x *GetOutSharesResponse) GetShares() []*ShareOut {
+	if x != nil {
+		return x.Shares
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_secure_aggregation_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Share); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+	return nil
+}
+
+// Privacy describes the noise added to a session's output
+type Privacy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mechanism   Mechanism `protobuf:"varint,1,opt,name=mechanism,proto3,enum=Mechanism" json:"mechanism,omitempty"`
+	Epsilon     float64   `protobuf:"fixed64,2,opt,name=epsilon,proto3" json:"epsilon,omitempty"`
+	Delta       float64   `protobuf:"fixed64,3,opt,name=delta,proto3" json:"delta,omitempty"`             // Only used by GAUSSIAN
+	Sensitivity float64   `protobuf:"fixed64,4,opt,name=sensitivity,proto3" json:"sensitivity,omitempty"` // Most one individual changes the output, L1 norm for LAPLACE and L2 for GAUSSIAN
+}
+
+func (x *Privacy) Reset() {
+	*x = Privacy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Privacy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Privacy) ProtoMessage() {}
+
+func (x *Privacy) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_secure_aggregation_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ShareOut); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Privacy.ProtoReflect.Descriptor instead.
+func (*Privacy) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Privacy) GetMechanism() Mechanism {
+	if x != nil {
+		return x.Mechanism
+	}
+	return Mechanism_NO_NOISE
+}
+
+func (x *Privacy) GetEpsilon() float64 {
+	if x != nil {
+		return x.Epsilon
+	}
+	return 0
+}
+
+func (x *Privacy) GetDelta() float64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *Privacy) GetSensitivity() float64 {
+	if x != nil {
+		return x.Sensitivity
+	}
+	return 0
+}
+
+type CreateSessionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Session      string   `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"` // Optional id, the server picks one when empty
+	Participants []string `protobuf:"bytes,2,rep,name=participants,proto3" json:"participants,omitempty"`
+	Scheme       Scheme   `protobuf:"varint,3,opt,name=scheme,proto3,enum=Scheme" json:"scheme,omitempty"`
+	Threshold    int32    `protobuf:"varint,4,opt,name=threshold,proto3" json:"threshold,omitempty"` // Shares needed to reconstruct, only used by SHAMIR
+	Length       int32    `protobuf:"varint,5,opt,name=length,proto3" json:"length,omitempty"`       // Elements of every input vector, 0 is the same as 1
+	Privacy      *Privacy `protobuf:"bytes,6,opt,name=privacy,proto3" json:"privacy,omitempty"`      // Noise added to the output, none when unset
+	Dataset      string   `protobuf:"bytes,7,opt,name=dataset,proto3" json:"dataset,omitempty"`      // Dataset the session queries, its privacy budget is charged per participant
+}
+
+func (x *CreateSessionRequest) Reset() {
+	*x = CreateSessionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSessionRequest) ProtoMessage() {}
+
+func (x *CreateSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_secure_aggregation_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Ack); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSessionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSessionRequest) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateSessionRequest) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+func (x *CreateSessionRequest) GetParticipants() []string {
+	if x != nil {
+		return x.Participants
+	}
+	return nil
+}
+
+func (x *CreateSessionRequest) GetScheme() Scheme {
+	if x != nil {
+		return x.Scheme
+	}
+	return Scheme_ADDITIVE
+}
+
+func (x *CreateSessionRequest) GetThreshold() int32 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *CreateSessionRequest) GetLength() int32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *CreateSessionRequest) GetPrivacy() *Privacy {
+	if x != nil {
+		return x.Privacy
+	}
+	return nil
+}
+
+func (x *CreateSessionRequest) GetDataset() string {
+	if x != nil {
+		return x.Dataset
+	}
+	return ""
+}
+
+type CreateSessionResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Session string `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+}
+
+func (x *CreateSessionResponse) Reset() {
+	*x = CreateSessionResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSessionResponse) ProtoMessage() {}
+
+func (x *CreateSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSessionResponse.ProtoReflect.Descriptor instead.
+func (*CreateSessionResponse) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateSessionResponse) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+type CloseSessionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Session string `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+}
+
+func (x *CloseSessionRequest) Reset() {
+	*x = CloseSessionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CloseSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseSessionRequest) ProtoMessage() {}
+
+func (x *CloseSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseSessionRequest.ProtoReflect.Descriptor instead.
+func (*CloseSessionRequest) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CloseSessionRequest) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+type Aggregate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Session string   `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	From    string   `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	Values  [][]byte `protobuf:"bytes,5,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (x *Aggregate) Reset() {
+	*x = Aggregate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Aggregate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Aggregate) ProtoMessage() {}
+
+func (x *Aggregate) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Aggregate.ProtoReflect.Descriptor instead.
+func (*Aggregate) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Aggregate) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+func (x *Aggregate) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *Aggregate) GetValues() [][]byte {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type GetAggregateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Session string `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+}
+
+func (x *GetAggregateRequest) Reset() {
+	*x = GetAggregateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAggregateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAggregateRequest) ProtoMessage() {}
+
+func (x *GetAggregateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAggregateRequest.ProtoReflect.Descriptor instead.
+func (*GetAggregateRequest) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetAggregateRequest) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+type GetAggregateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*Aggregate `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // One per participant that published
+}
+
+func (x *GetAggregateResponse) Reset() {
+	*x = GetAggregateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAggregateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAggregateResponse) ProtoMessage() {}
+
+func (x *GetAggregateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAggregateResponse.ProtoReflect.Descriptor instead.
+func (*GetAggregateResponse) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetAggregateResponse) GetResults() []*Aggregate {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type GetTriplesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Participant string `protobuf:"bytes,1,opt,name=participant,proto3" json:"participant,omitempty"`
+	Session     string `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`
+	Offset      uint32 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"` // Index of the first triple, triples are numbered per session
+	Count       uint32 `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *GetTriplesRequest) Reset() {
+	*x = GetTriplesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTriplesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTriplesRequest) ProtoMessage() {}
+
+func (x *GetTriplesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTriplesRequest.ProtoReflect.Descriptor instead.
+func (*GetTriplesRequest) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetTriplesRequest) GetParticipant() string {
+	if x != nil {
+		return x.Participant
+	}
+	return ""
+}
+
+func (x *GetTriplesRequest) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+func (x *GetTriplesRequest) GetOffset() uint32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetTriplesRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// Triple is a participant's additive share of random a, b and c = a * b
+type Triple struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	A []byte `protobuf:"bytes,1,opt,name=a,proto3" json:"a,omitempty"`
+	B []byte `protobuf:"bytes,2,opt,name=b,proto3" json:"b,omitempty"`
+	C []byte `protobuf:"bytes,3,opt,name=c,proto3" json:"c,omitempty"`
+}
+
+func (x *Triple) Reset() {
+	*x = Triple{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Triple) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Triple) ProtoMessage() {}
+
+func (x *Triple) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Triple.ProtoReflect.Descriptor instead.
+func (*Triple) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Triple) GetA() []byte {
+	if x != nil {
+		return x.A
+	}
+	return nil
+}
+
+func (x *Triple) GetB() []byte {
+	if x != nil {
+		return x.B
+	}
+	return nil
+}
+
+func (x *Triple) GetC() []byte {
+	if x != nil {
+		return x.C
+	}
+	return nil
+}
+
+type GetTriplesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Triples []*Triple `protobuf:"bytes,1,rep,name=triples,proto3" json:"triples,omitempty"`
+}
+
+func (x *GetTriplesResponse) Reset() {
+	*x = GetTriplesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTriplesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTriplesResponse) ProtoMessage() {}
+
+func (x *GetTriplesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTriplesResponse.ProtoReflect.Descriptor instead.
+func (*GetTriplesResponse) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetTriplesResponse) GetTriples() []*Triple {
+	if x != nil {
+		return x.Triples
+	}
+	return nil
+}
+
+type Opening struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From    string   `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To      string   `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Session string   `protobuf:"bytes,3,opt,name=session,proto3" json:"session,omitempty"`
+	Seq     uint64   `protobuf:"varint,4,opt,name=seq,proto3" json:"seq,omitempty"`    // Sequence number, unique per sender within the session
+	Round   string   `protobuf:"bytes,5,opt,name=round,proto3" json:"round,omitempty"` // Names the opening, the same for all participants
+	Values  [][]byte `protobuf:"bytes,6,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (x *Opening) Reset() {
+	*x = Opening{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Opening) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Opening) ProtoMessage() {}
+
+func (x *Opening) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Opening.ProtoReflect.Descriptor instead.
+func (*Opening) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Opening) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *Opening) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *Opening) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+func (x *Opening) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *Opening) GetRound() string {
+	if x != nil {
+		return x.Round
+	}
+	return ""
+}
+
+func (x *Opening) GetValues() [][]byte {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type GetOpenedRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Participant string `protobuf:"bytes,1,opt,name=participant,proto3" json:"participant,omitempty"`
+	Session     string `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`
+	Round       string `protobuf:"bytes,3,opt,name=round,proto3" json:"round,omitempty"`
+}
+
+func (x *GetOpenedRequest) Reset() {
+	*x = GetOpenedRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOpenedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOpenedRequest) ProtoMessage() {}
+
+func (x *GetOpenedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOpenedRequest.ProtoReflect.Descriptor instead.
+func (*GetOpenedRequest) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetOpenedRequest) GetParticipant() string {
+	if x != nil {
+		return x.Participant
+	}
+	return ""
+}
+
+func (x *GetOpenedRequest) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+func (x *GetOpenedRequest) GetRound() string {
+	if x != nil {
+		return x.Round
+	}
+	return ""
+}
+
+type GetOpenedResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Values [][]byte `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"` // Element-wise sum of the other participants' shares
+}
+
+func (x *GetOpenedResponse) Reset() {
+	*x = GetOpenedResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOpenedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOpenedResponse) ProtoMessage() {}
+
+func (x *GetOpenedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOpenedResponse.ProtoReflect.Descriptor instead.
+func (*GetOpenedResponse) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetOpenedResponse) GetValues() [][]byte {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type GetBitMasksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Participant string `protobuf:"bytes,1,opt,name=participant,proto3" json:"participant,omitempty"`
+	Session     string `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`
+	Offset      uint32 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"` // Index of the first mask, masks are numbered per session
+	Count       uint32 `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *GetBitMasksRequest) Reset() {
+	*x = GetBitMasksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBitMasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBitMasksRequest) ProtoMessage() {}
+
+func (x *GetBitMasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBitMasksRequest.ProtoReflect.Descriptor instead.
+func (*GetBitMasksRequest) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetBitMasksRequest) GetParticipant() string {
+	if x != nil {
+		return x.Participant
+	}
+	return ""
+}
+
+func (x *GetBitMasksRequest) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+func (x *GetBitMasksRequest) GetOffset() uint32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetBitMasksRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// BitMask is a participant's additive share of a random field element r and of
+// each of its bits
+type BitMask struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value []byte   `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Bits  [][]byte `protobuf:"bytes,2,rep,name=bits,proto3" json:"bits,omitempty"` // Least significant bit first, one per bit of the modulus
+}
+
+func (x *BitMask) Reset() {
+	*x = BitMask{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BitMask) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BitMask) ProtoMessage() {}
+
+func (x *BitMask) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BitMask.ProtoReflect.Descriptor instead.
+func (*BitMask) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *BitMask) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *BitMask) GetBits() [][]byte {
+	if x != nil {
+		return x.Bits
+	}
+	return nil
+}
+
+type GetBitMasksResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Masks []*BitMask `protobuf:"bytes,1,rep,name=masks,proto3" json:"masks,omitempty"`
+}
+
+func (x *GetBitMasksResponse) Reset() {
+	*x = GetBitMasksResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBitMasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBitMasksResponse) ProtoMessage() {}
+
+func (x *GetBitMasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBitMasksResponse.ProtoReflect.Descriptor instead.
+func (*GetBitMasksResponse) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetBitMasksResponse) GetMasks() []*BitMask {
+	if x != nil {
+		return x.Masks
+	}
+	return nil
+}
+
+type GetBudgetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Participant string `protobuf:"bytes,1,opt,name=participant,proto3" json:"participant,omitempty"` // Only this participant, all when empty
+	Dataset     string `protobuf:"bytes,2,opt,name=dataset,proto3" json:"dataset,omitempty"`         // Only this dataset, all when empty
+}
+
+func (x *GetBudgetRequest) Reset() {
+	*x = GetBudgetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBudgetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBudgetRequest) ProtoMessage() {}
+
+func (x *GetBudgetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBudgetRequest.ProtoReflect.Descriptor instead.
+func (*GetBudgetRequest) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetBudgetRequest) GetParticipant() string {
+	if x != nil {
+		return x.Participant
+	}
+	return ""
+}
+
+func (x *GetBudgetRequest) GetDataset() string {
+	if x != nil {
+		return x.Dataset
+	}
+	return ""
+}
+
+// Budget is the privacy budget a participant spent on a dataset
+type Budget struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Participant string  `protobuf:"bytes,1,opt,name=participant,proto3" json:"participant,omitempty"`
+	Dataset     string  `protobuf:"bytes,2,opt,name=dataset,proto3" json:"dataset,omitempty"`
+	Epsilon     float64 `protobuf:"fixed64,3,opt,name=epsilon,proto3" json:"epsilon,omitempty"` // Spent so far
+	Delta       float64 `protobuf:"fixed64,4,opt,name=delta,proto3" json:"delta,omitempty"`
+	Sessions    int32   `protobuf:"varint,5,opt,name=sessions,proto3" json:"sessions,omitempty"`      // Sessions that spent it
+	MaxEpsilon  float64 `protobuf:"fixed64,6,opt,name=maxEpsilon,proto3" json:"maxEpsilon,omitempty"` // Limit across all sessions
+	MaxDelta    float64 `protobuf:"fixed64,7,opt,name=maxDelta,proto3" json:"maxDelta,omitempty"`
+}
+
+func (x *Budget) Reset() {
+	*x = Budget{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Budget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Budget) ProtoMessage() {}
+
+func (x *Budget) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Budget.ProtoReflect.Descriptor instead.
+func (*Budget) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Budget) GetParticipant() string {
+	if x != nil {
+		return x.Participant
+	}
+	return ""
+}
+
+func (x *Budget) GetDataset() string {
+	if x != nil {
+		return x.Dataset
+	}
+	return ""
+}
+
+func (x *Budget) GetEpsilon() float64 {
+	if x != nil {
+		return x.Epsilon
+	}
+	return 0
+}
+
+func (x *Budget) GetDelta() float64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *Budget) GetSessions() int32 {
+	if x != nil {
+		return x.Sessions
+	}
+	return 0
+}
+
+func (x *Budget) GetMaxEpsilon() float64 {
+	if x != nil {
+		return x.MaxEpsilon
+	}
+	return 0
+}
+
+func (x *Budget) GetMaxDelta() float64 {
+	if x != nil {
+		return x.MaxDelta
+	}
+	return 0
+}
+
+type GetBudgetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Budgets []*Budget `protobuf:"bytes,1,rep,name=budgets,proto3" json:"budgets,omitempty"`
+}
+
+func (x *GetBudgetResponse) Reset() {
+	*x = GetBudgetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_secure_aggregation_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBudgetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBudgetResponse) ProtoMessage() {}
+
+func (x *GetBudgetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_secure_aggregation_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBudgetResponse.ProtoReflect.Descriptor instead.
+func (*GetBudgetResponse) Descriptor() ([]byte, []int) {
+	return file_secure_aggregation_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetBudgetResponse) GetBudgets() []*Budget {
+	if x != nil {
+		return x.Budgets
+	}
+	return nil
+}
+
+var File_secure_aggregation_proto protoreflect.FileDescriptor
+
+var file_secure_aggregation_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x5f, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x79, 0x0a, 0x05, 0x53, 0x68,
+	0x61, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x61, 0x72, 0x74, 0x73, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x0c, 0x52, 0x05, 0x70, 0x61, 0x72, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f,
+	0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a,
+	0x02, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x18, 0x0a,
+	0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x73, 0x65, 0x71, 0x4a, 0x04, 0x08, 0x01, 0x10, 0x02, 0x4a,
+	0x04, 0x08, 0x06, 0x10, 0x07, 0x22, 0x7a, 0x0a, 0x08, 0x53, 0x68, 0x61, 0x72, 0x65, 0x4f, 0x75,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x07, 0x20,
+	0x03, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x03, 0x73, 0x65, 0x71, 0x4a, 0x04, 0x08, 0x03, 0x10, 0x04, 0x4a, 0x04, 0x08, 0x06, 0x10,
+	0x07, 0x22, 0x1f, 0x0a, 0x03, 0x41, 0x63, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x22, 0x53, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x65, 0x64, 0x53, 0x68,
+	0x61, 0x72, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x70,
+	0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x12, 0x18, 0x0a,
+	0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x46, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x41, 0x64,
+	0x64, 0x65, 0x64, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x20, 0x0a, 0x0b, 0x61, 0x64, 0x64, 0x65, 0x64, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0b, 0x61, 0x64, 0x64, 0x65, 0x64, 0x53, 0x68, 0x61,
+	0x72, 0x65, 0x73, 0x4a, 0x04, 0x08, 0x01, 0x10, 0x02, 0x4a, 0x04, 0x08, 0x02, 0x10, 0x03, 0x22,
+	0x50, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x65, 0x64, 0x4f, 0x75, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69,
+	0x70, 0x61, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x61, 0x72, 0x74,
+	0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x22, 0x3d, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x65, 0x64, 0x4f, 0x75, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x64, 0x64, 0x65,
+	0x64, 0x4f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x08, 0x61, 0x64, 0x64, 0x65,
+	0x64, 0x4f, 0x75, 0x74, 0x4a, 0x04, 0x08, 0x01, 0x10, 0x02, 0x4a, 0x04, 0x08, 0x02, 0x10, 0x03,
+	0x22, 0x6b, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69,
+	0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x61,
+	0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x22, 0x39, 0x0a,
+	0x14, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x72, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x53, 0x68, 0x61, 0x72, 0x65, 0x4f, 0x75, 0x74,
+	0x52, 0x06, 0x73, 0x68, 0x61, 0x72, 0x65, 0x73, 0x22, 0x85, 0x01, 0x0a, 0x07, 0x50, 0x72, 0x69,
+	0x76, 0x61, 0x63, 0x79, 0x12, 0x28, 0x0a, 0x09, 0x6d, 0x65, 0x63, 0x68, 0x61, 0x6e, 0x69, 0x73,
+	0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x4d, 0x65, 0x63, 0x68, 0x61, 0x6e,
+	0x69, 0x73, 0x6d, 0x52, 0x09, 0x6d, 0x65, 0x63, 0x68, 0x61, 0x6e, 0x69, 0x73, 0x6d, 0x12, 0x18,
+	0x0a, 0x07, 0x65, 0x70, 0x73, 0x69, 0x6c, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x07, 0x65, 0x70, 0x73, 0x69, 0x6c, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x65, 0x6c, 0x74,
+	0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x20,
+	0x0a, 0x0b, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0b, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79,
+	0x22, 0xe9, 0x01, 0x0a, 0x14, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61,
+	0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x61, 0x72, 0x74, 0x69,
+	0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x1f, 0x0a, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x07, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x65,
+	0x52, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x68, 0x72, 0x65,
+	0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x74, 0x68, 0x72,
+	0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x22,
+	0x0a, 0x07, 0x70, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x08, 0x2e, 0x50, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x52, 0x07, 0x70, 0x72, 0x69, 0x76, 0x61,
+	0x63, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x22, 0x31, 0x0a, 0x15,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x22,
+	0x2f, 0x0a, 0x13, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x22, 0x5d, 0x0a, 0x09, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x06, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x73, 0x4a, 0x04, 0x08, 0x03, 0x10, 0x04, 0x4a, 0x04, 0x08, 0x04, 0x10, 0x05, 0x22,
+	0x2f, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x22, 0x3c, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x41, 0x67, 0x67, 0x72,
+	0x65, 0x67, 0x61, 0x74, 0x65, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x7d,
+	0x0a, 0x11, 0x47, 0x65, 0x74, 0x54, 0x72, 0x69, 0x70, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63,
+	0x69, 0x70, 0x61, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x32, 0x0a,
+	0x06, 0x54, 0x72, 0x69, 0x70, 0x6c, 0x65, 0x12, 0x0c, 0x0a, 0x01, 0x61, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x01, 0x61, 0x12, 0x0c, 0x0a, 0x01, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x01, 0x62, 0x12, 0x0c, 0x0a, 0x01, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01,
+	0x63, 0x22, 0x37, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x54, 0x72, 0x69, 0x70, 0x6c, 0x65, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x07, 0x74, 0x72, 0x69, 0x70, 0x6c,
+	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x54, 0x72, 0x69, 0x70, 0x6c,
+	0x65, 0x52, 0x07, 0x74, 0x72, 0x69, 0x70, 0x6c, 0x65, 0x73, 0x22, 0x87, 0x01, 0x0a, 0x07, 0x4f,
+	0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x16, 0x0a, 0x06,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x06, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x73, 0x22, 0x64, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x4f, 0x70, 0x65, 0x6e, 0x65,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x74,
+	0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70,
+	0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x22, 0x2b, 0x0a, 0x11, 0x47, 0x65,
+	0x74, 0x4f, 0x70, 0x65, 0x6e, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52,
+	0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x7e, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x42, 0x69,
+	0x74, 0x4d, 0x61, 0x73, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a,
+	0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x12,
+	0x18, 0x0a, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x33, 0x0a, 0x07, 0x42, 0x69, 0x74, 0x4d, 0x61,
+	0x73, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x69, 0x74, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x04, 0x62, 0x69, 0x74, 0x73, 0x22, 0x35, 0x0a, 0x13,
+	0x47, 0x65, 0x74, 0x42, 0x69, 0x74, 0x4d, 0x61, 0x73, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x05, 0x6d, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x08, 0x2e, 0x42, 0x69, 0x74, 0x4d, 0x61, 0x73, 0x6b, 0x52, 0x05, 0x6d, 0x61,
+	0x73, 0x6b, 0x73, 0x22, 0x4e, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69,
+	0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x61,
+	0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x61, 0x74,
+	0x61, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x74, 0x61,
+	0x73, 0x65, 0x74, 0x22, 0xcc, 0x01, 0x0a, 0x06, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74, 0x12, 0x20,
+	0x0a, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x70,
+	0x73, 0x69, 0x6c, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x65, 0x70, 0x73,
+	0x69, 0x6c, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x05, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x45, 0x70, 0x73,
+	0x69, 0x6c, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x45,
+	0x70, 0x73, 0x69, 0x6c, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x61, 0x78, 0x44, 0x65, 0x6c,
+	0x74, 0x61, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x6d, 0x61, 0x78, 0x44, 0x65, 0x6c,
+	0x74, 0x61, 0x22, 0x36, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x07, 0x62, 0x75, 0x64, 0x67, 0x65,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x42, 0x75, 0x64, 0x67, 0x65,
+	0x74, 0x52, 0x07, 0x62, 0x75, 0x64, 0x67, 0x65, 0x74, 0x73, 0x2a, 0x22, 0x0a, 0x06, 0x53, 0x63,
+	0x68, 0x65, 0x6d, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x44, 0x44, 0x49, 0x54, 0x49, 0x56, 0x45,
+	0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x48, 0x41, 0x4d, 0x49, 0x52, 0x10, 0x01, 0x2a, 0x34,
+	0x0a, 0x09, 0x4d, 0x65, 0x63, 0x68, 0x61, 0x6e, 0x69, 0x73, 0x6d, 0x12, 0x0c, 0x0a, 0x08, 0x4e,
+	0x4f, 0x5f, 0x4e, 0x4f, 0x49, 0x53, 0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x4c, 0x41, 0x50,
+	0x4c, 0x41, 0x43, 0x45, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x47, 0x41, 0x55, 0x53, 0x53, 0x49,
+	0x41, 0x4e, 0x10, 0x02, 0x32, 0xd5, 0x05, 0x0a, 0x14, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x53,
+	0x68, 0x61, 0x72, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1b, 0x0a,
+	0x09, 0x53, 0x65, 0x6e, 0x64, 0x53, 0x68, 0x61, 0x72, 0x65, 0x12, 0x06, 0x2e, 0x53, 0x68, 0x61,
+	0x72, 0x65, 0x1a, 0x04, 0x2e, 0x41, 0x63, 0x6b, 0x22, 0x00, 0x12, 0x1f, 0x0a, 0x0c, 0x53, 0x65,
+	0x6e, 0x64, 0x53, 0x68, 0x61, 0x72, 0x65, 0x4f, 0x75, 0x74, 0x12, 0x09, 0x2e, 0x53, 0x68, 0x61,
+	0x72, 0x65, 0x4f, 0x75, 0x74, 0x1a, 0x04, 0x2e, 0x41, 0x63, 0x6b, 0x12, 0x41, 0x0a, 0x0e, 0x47,
+	0x65, 0x74, 0x41, 0x64, 0x64, 0x65, 0x64, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x12, 0x16, 0x2e,
+	0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x65, 0x64, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x65, 0x64,
+	0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38,
+	0x0a, 0x0b, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x65, 0x64, 0x4f, 0x75, 0x74, 0x12, 0x13, 0x2e,
+	0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x65, 0x64, 0x4f, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x14, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x65, 0x64, 0x4f, 0x75, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x4f,
+	0x75, 0x74, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x12, 0x14, 0x2e, 0x47, 0x65, 0x74, 0x4f, 0x75,
+	0x74, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15,
+	0x2e, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x15, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x0c, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x2e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x53, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x04, 0x2e, 0x41, 0x63,
+	0x6b, 0x12, 0x24, 0x0a, 0x10, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x41, 0x67, 0x67, 0x72,
+	0x65, 0x67, 0x61, 0x74, 0x65, 0x12, 0x0a, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74,
+	0x65, 0x1a, 0x04, 0x2e, 0x41, 0x63, 0x6b, 0x12, 0x3b, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x41, 0x67,
+	0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x12, 0x14, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x67, 0x67,
+	0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e,
+	0x47, 0x65, 0x74, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x54, 0x72, 0x69, 0x70, 0x6c,
+	0x65, 0x73, 0x12, 0x12, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x69, 0x70, 0x6c, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x69, 0x70,
+	0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0b, 0x53,
+	0x65, 0x6e, 0x64, 0x4f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x08, 0x2e, 0x4f, 0x70, 0x65,
+	0x6e, 0x69, 0x6e, 0x67, 0x1a, 0x04, 0x2e, 0x41, 0x63, 0x6b, 0x12, 0x32, 0x0a, 0x09, 0x47, 0x65,
+	0x74, 0x4f, 0x70, 0x65, 0x6e, 0x65, 0x64, 0x12, 0x11, 0x2e, 0x47, 0x65, 0x74, 0x4f, 0x70, 0x65,
+	0x6e, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x47, 0x65, 0x74,
+	0x4f, 0x70, 0x65, 0x6e, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38,
+	0x0a, 0x0b, 0x47, 0x65, 0x74, 0x42, 0x69, 0x74, 0x4d, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x13, 0x2e,
+	0x47, 0x65, 0x74, 0x42, 0x69, 0x74, 0x4d, 0x61, 0x73, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x14, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x69, 0x74, 0x4d, 0x61, 0x73, 0x6b, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x42,
+	0x75, 0x64, 0x67, 0x65, 0x74, 0x12, 0x11, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x75, 0x64, 0x67, 0x65,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x75,
+	0x64, 0x67, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x04, 0x5a, 0x02,
+	0x2e, 0x2f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_secure_aggregation_proto_rawDescOnce sync.Once
+	file_secure_aggregation_proto_rawDescData = file_secure_aggregation_proto_rawDesc
+)
+
+func file_secure_aggregation_proto_rawDescGZIP() []byte {
+	file_secure_aggregation_proto_rawDescOnce.Do(func() {
+		file_secure_aggregation_proto_rawDescData = protoimpl.X.CompressGZIP(file_secure_aggregation_proto_rawDescData)
+	})
+	return file_secure_aggregation_proto_rawDescData
+}
+
+var file_secure_aggregation_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_secure_aggregation_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
+var file_secure_aggregation_proto_goTypes = []interface{}{
+	(Scheme)(0),                    // 0: Scheme
+	(Mechanism)(0),                 // 1: Mechanism
+	(*Share)(nil),                  // 2: Share
+	(*ShareOut)(nil),               // 3: ShareOut
+	(*Ack)(nil),                    // 4: Ack
+	(*GetAddedSharesRequest)(nil),  // 5: GetAddedSharesRequest
+	(*GetAddedSharesResponse)(nil), // 6: GetAddedSharesResponse
+	(*GetAddedOutRequest)(nil),     // 7: GetAddedOutRequest
+	(*GetAddedOutResponse)(nil),    // 8: GetAddedOutResponse
+	(*GetOutSharesRequest)(nil),    // 9: GetOutSharesRequest
+	(*GetOutSharesResponse)(nil),   // 10: GetOutSharesResponse
+	(*Privacy)(nil),                // 11: Privacy
+	(*CreateSessionRequest)(nil),   // 12: CreateSessionRequest
+	(*CreateSessionResponse)(nil),  // 13: CreateSessionResponse
+	(*CloseSessionRequest)(nil),    // 14: CloseSessionRequest
+	(*Aggregate)(nil),              // 15: Aggregate
+	(*GetAggregateRequest)(nil),    // 16: GetAggregateRequest
+	(*GetAggregateResponse)(nil),   // 17: GetAggregateResponse
+	(*GetTriplesRequest)(nil),      // 18: GetTriplesRequest
+	(*Triple)(nil),                 // 19: Triple
+	(*GetTriplesResponse)(nil),     // 20: GetTriplesResponse
+	(*Opening)(nil),                // 21: Opening
+	(*GetOpenedRequest)(nil),       // 22: GetOpenedRequest
+	(*GetOpenedResponse)(nil),      // 23: GetOpenedResponse
+	(*GetBitMasksRequest)(nil),     // 24: GetBitMasksRequest
+	(*BitMask)(nil),                // 25: BitMask
+	(*GetBitMasksResponse)(nil),    // 26: GetBitMasksResponse
+	(*GetBudgetRequest)(nil),       // 27: GetBudgetRequest
+	(*Budget)(nil),                 // 28: Budget
+	(*GetBudgetResponse)(nil),      // 29: GetBudgetResponse
+}
+var file_secure_aggregation_proto_depIdxs = []int32{
+	3,  // 0: GetOutSharesResponse.shares:type_name -> ShareOut
+	1,  // 1: Privacy.mechanism:type_name -> Mechanism
+	0,  // 2: CreateSessionRequest.scheme:type_name -> Scheme
+	11, // 3: CreateSessionRequest.privacy:type_name -> Privacy
+	15, // 4: GetAggregateResponse.results:type_name -> Aggregate
+	19, // 5: GetTriplesResponse.triples:type_name -> Triple
+	25, // 6: GetBitMasksResponse.masks:type_name -> BitMask
+	28, // 7: GetBudgetResponse.budgets:type_name -> Budget
+	2,  // 8: SecretSharingService.SendShare:input_type -> Share
+	3,  // 9: SecretSharingService.SendShareOut:input_type -> ShareOut
+	5,  // 10: SecretSharingService.GetAddedShares:input_type -> GetAddedSharesRequest
+	7,  // 11: SecretSharingService.GetAddedOut:input_type -> GetAddedOutRequest
+	9,  // 12: SecretSharingService.GetOutShares:input_type -> GetOutSharesRequest
+	12, // 13: SecretSharingService.CreateSession:input_type -> CreateSessionRequest
+	14, // 14: SecretSharingService.CloseSession:input_type -> CloseSessionRequest
+	15, // 15: SecretSharingService.PublishAggregate:input_type -> Aggregate
+	16, // 16: SecretSharingService.GetAggregate:input_type -> GetAggregateRequest
+	18, // 17: SecretSharingService.GetTriples:input_type -> GetTriplesRequest
+	21, // 18: SecretSharingService.SendOpening:input_type -> Opening
+	22, // 19: SecretSharingService.GetOpened:input_type -> GetOpenedRequest
+	24, // 20: SecretSharingService.GetBitMasks:input_type -> GetBitMasksRequest
+	27, // 21: SecretSharingService.GetBudget:input_type -> GetBudgetRequest
+	4,  // 22: SecretSharingService.SendShare:output_type -> Ack
+	4,  // 23: SecretSharingService.SendShareOut:output_type -> Ack
+	6,  // 24: SecretSharingService.GetAddedShares:output_type -> GetAddedSharesResponse
+	8,  // 25: SecretSharingService.GetAddedOut:output_type -> GetAddedOutResponse
+	10, // 26: SecretSharingService.GetOutShares:output_type -> GetOutSharesResponse
+	13, // 27: SecretSharingService.CreateSession:output_type -> CreateSessionResponse
+	4,  // 28: SecretSharingService.CloseSession:output_type -> Ack
+	4,  // 29: SecretSharingService.PublishAggregate:output_type -> Ack
+	17, // 30: SecretSharingService.GetAggregate:output_type -> GetAggregateResponse
+	20, // 31: SecretSharingService.GetTriples:output_type -> GetTriplesResponse
+	4,  // 32: SecretSharingService.SendOpening:output_type -> Ack
+	23, // 33: SecretSharingService.GetOpened:output_type -> GetOpenedResponse
+	26, // 34: SecretSharingService.GetBitMasks:output_type -> GetBitMasksResponse
+	29, // 35: SecretSharingService.GetBudget:output_type -> GetBudgetResponse
+	22, // [22:36] is the sub-list for method output_type
+	8,  // [8:22] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_secure_aggregation_proto_init() }
+func file_secure_aggregation_proto_init() {
+	if File_secure_aggregation_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_secure_aggregation_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Share); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShareOut); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Ack); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
 				return &v.unknownFields
 			default:
 				return nil
@@ -556,19 +2205,272 @@ func file_secure_aggregation_proto_init() {
 				return nil
 			}
 		}
+		file_secure_aggregation_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOutSharesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOutSharesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Privacy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateSessionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateSessionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CloseSessionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Aggregate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetAggregateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetAggregateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTriplesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Triple); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTriplesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Opening); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOpenedRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOpenedResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBitMasksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BitMask); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBitMasksResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBudgetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Budget); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_secure_aggregation_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBudgetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_secure_aggregation_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   7,
+			NumEnums:      2,
+			NumMessages:   28,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_secure_aggregation_proto_goTypes,
 		DependencyIndexes: file_secure_aggregation_proto_depIdxs,
+		EnumInfos:         file_secure_aggregation_proto_enumTypes,
 		MessageInfos:      file_secure_aggregation_proto_msgTypes,
 	}.Build()
 	File_secure_aggregation_proto = out.File
@@ -27,6 +27,35 @@ type SecretSharingServiceClient interface {
 	SendShareOut(ctx context.Context, in *ShareOut, opts ...grpc.CallOption) (*Ack, error)
 	GetAddedShares(ctx context.Context, in *GetAddedSharesRequest, opts ...grpc.CallOption) (*GetAddedSharesResponse, error)
 	GetAddedOut(ctx context.Context, in *GetAddedOutRequest, opts ...grpc.CallOption) (*GetAddedOutResponse, error)
+	// GetOutShares waits for at least a minimum number of out shares sent to a
+	// participant and returns them individually, for threshold reconstruction
+	GetOutShares(ctx context.Context, in *GetOutSharesRequest, opts ...grpc.CallOption) (*GetOutSharesResponse, error)
+	// CreateSession starts a new aggregation round with its own state
+	CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*CreateSessionResponse, error)
+	// CloseSession drops all state kept for a session, once every participant
+	// closed it
+	CloseSession(ctx context.Context, in *CloseSessionRequest, opts ...grpc.CallOption) (*Ack, error)
+	// PublishAggregate records a participant's final result of a session, used
+	// in peer-to-peer mode where the shares never reach the central server
+	PublishAggregate(ctx context.Context, in *Aggregate, opts ...grpc.CallOption) (*Ack, error)
+	// GetAggregate returns the results published for a session
+	GetAggregate(ctx context.Context, in *GetAggregateRequest, opts ...grpc.CallOption) (*GetAggregateResponse, error)
+	// GetTriples hands a participant its shares of Beaver triples for
+	// multiplying shared values; the server acts as trusted dealer
+	GetTriples(ctx context.Context, in *GetTriplesRequest, opts ...grpc.CallOption) (*GetTriplesResponse, error)
+	// SendOpening sends a participant's share of a value every participant
+	// learns, such as the masked inputs of a multiplication
+	SendOpening(ctx context.Context, in *Opening, opts ...grpc.CallOption) (*Ack, error)
+	// GetOpened waits for the other participants' shares of an opening and
+	// returns their sum
+	GetOpened(ctx context.Context, in *GetOpenedRequest, opts ...grpc.CallOption) (*GetOpenedResponse, error)
+	// GetBitMasks hands a participant its shares of random masks together with
+	// shares of their bits, for comparing shared values; the server acts as
+	// trusted dealer
+	GetBitMasks(ctx context.Context, in *GetBitMasksRequest, opts ...grpc.CallOption) (*GetBitMasksResponse, error)
+	// GetBudget returns the privacy budget participants spent per dataset, as
+	// recorded in the server's ledger
+	GetBudget(ctx context.Context, in *GetBudgetRequest, opts ...grpc.CallOption) (*GetBudgetResponse, error)
 }
 
 type secretSharingServiceClient struct {
@@ -73,6 +102,96 @@ func (c *secretSharingServiceClient) GetAddedOut(ctx context.Context, in *GetAdd
 	return out, nil
 }
 
+func (c *secretSharingServiceClient) GetOutShares(ctx context.Context, in *GetOutSharesRequest, opts ...grpc.CallOption) (*GetOutSharesResponse, error) {
+	out := new(GetOutSharesResponse)
+	err := c.cc.Invoke(ctx, "/SecretSharingService/GetOutShares", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretSharingServiceClient) CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*CreateSessionResponse, error) {
+	out := new(CreateSessionResponse)
+	err := c.cc.Invoke(ctx, "/SecretSharingService/CreateSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretSharingServiceClient) CloseSession(ctx context.Context, in *CloseSessionRequest, opts ...grpc.CallOption) (*Ack, error) {
+	out := new(Ack)
+	err := c.cc.Invoke(ctx, "/SecretSharingService/CloseSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretSharingServiceClient) PublishAggregate(ctx context.Context, in *Aggregate, opts ...grpc.CallOption) (*Ack, error) {
+	out := new(Ack)
+	err := c.cc.Invoke(ctx, "/SecretSharingService/PublishAggregate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretSharingServiceClient) GetAggregate(ctx context.Context, in *GetAggregateRequest, opts ...grpc.CallOption) (*GetAggregateResponse, error) {
+	out := new(GetAggregateResponse)
+	err := c.cc.Invoke(ctx, "/SecretSharingService/GetAggregate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretSharingServiceClient) GetTriples(ctx context.Context, in *GetTriplesRequest, opts ...grpc.CallOption) (*GetTriplesResponse, error) {
+	out := new(GetTriplesResponse)
+	err := c.cc.Invoke(ctx, "/SecretSharingService/GetTriples", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretSharingServiceClient) SendOpening(ctx context.Context, in *Opening, opts ...grpc.CallOption) (*Ack, error) {
+	out := new(Ack)
+	err := c.cc.Invoke(ctx, "/SecretSharingService/SendOpening", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretSharingServiceClient) GetOpened(ctx context.Context, in *GetOpenedRequest, opts ...grpc.CallOption) (*GetOpenedResponse, error) {
+	out := new(GetOpenedResponse)
+	err := c.cc.Invoke(ctx, "/SecretSharingService/GetOpened", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretSharingServiceClient) GetBitMasks(ctx context.Context, in *GetBitMasksRequest, opts ...grpc.CallOption) (*GetBitMasksResponse, error) {
+	out := new(GetBitMasksResponse)
+	err := c.cc.Invoke(ctx, "/SecretSharingService/GetBitMasks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretSharingServiceClient) GetBudget(ctx context.Context, in *GetBudgetRequest, opts ...grpc.CallOption) (*GetBudgetResponse, error) {
+	out := new(GetBudgetResponse)
+	err := c.cc.Invoke(ctx, "/SecretSharingService/GetBudget", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SecretSharingServiceServer is the server API for SecretSharingService service.
 // All implementations must embed UnimplementedSecretSharingServiceServer
 // for forward compatibility
@@ -82,6 +201,35 @@ type SecretSharingServiceServer interface {
 	SendShareOut(context.Context, *ShareOut) (*Ack, error)
 	GetAddedShares(context.Context, *GetAddedSharesRequest) (*GetAddedSharesResponse, error)
 	GetAddedOut(context.Context, *GetAddedOutRequest) (*GetAddedOutResponse, error)
+	// GetOutShares waits for at least a minimum number of out shares sent to a
+	// participant and returns them individually, for threshold reconstruction
+	GetOutShares(context.Context, *GetOutSharesRequest) (*GetOutSharesResponse, error)
+	// CreateSession starts a new aggregation round with its own state
+	CreateSession(context.Context, *CreateSessionRequest) (*CreateSessionResponse, error)
+	// CloseSession drops all state kept for a session, once every participant
+	// closed it
+	CloseSession(context.Context, *CloseSessionRequest) (*Ack, error)
+	// PublishAggregate records a participant's final result of a session, used
+	// in peer-to-peer mode where the shares never reach the central server
+	PublishAggregate(context.Context, *Aggregate) (*Ack, error)
+	// GetAggregate returns the results published for a session
+	GetAggregate(context.Context, *GetAggregateRequest) (*GetAggregateResponse, error)
+	// GetTriples hands a participant its shares of Beaver triples for
+	// multiplying shared values; the server acts as trusted dealer
+	GetTriples(context.Context, *GetTriplesRequest) (*GetTriplesResponse, error)
+	// SendOpening sends a participant's share of a value every participant
+	// learns, such as the masked inputs of a multiplication
+	SendOpening(context.Context, *Opening) (*Ack, error)
+	// GetOpened waits for the other participants' shares of an opening and
+	// returns their sum
+	GetOpened(context.Context, *GetOpenedRequest) (*GetOpenedResponse, error)
+	// GetBitMasks hands a participant its shares of random masks together with
+	// shares of their bits, for comparing shared values; the server acts as
+	// trusted dealer
+	GetBitMasks(context.Context, *GetBitMasksRequest) (*GetBitMasksResponse, error)
+	// GetBudget returns the privacy budget participants spent per dataset, as
+	// recorded in the server's ledger
+	GetBudget(context.Context, *GetBudgetRequest) (*GetBudgetResponse, error)
 	mustEmbedUnimplementedSecretSharingServiceServer()
 }
 
@@ -101,6 +249,36 @@ func (UnimplementedSecretSharingServiceServer) GetAddedShares(context.Context, *
 func (UnimplementedSecretSharingServiceServer) GetAddedOut(context.Context, *GetAddedOutRequest) (*GetAddedOutResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAddedOut not implemented")
 }
+func (UnimplementedSecretSharingServiceServer) GetOutShares(context.Context, *GetOutSharesRequest) (*GetOutSharesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOutShares not implemented")
+}
+func (UnimplementedSecretSharingServiceServer) CreateSession(context.Context, *CreateSessionRequest) (*CreateSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSession not implemented")
+}
+func (UnimplementedSecretSharingServiceServer) CloseSession(context.Context, *CloseSessionRequest) (*Ack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseSession not implemented")
+}
+func (UnimplementedSecretSharingServiceServer) PublishAggregate(context.Context, *Aggregate) (*Ack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishAggregate not implemented")
+}
+func (UnimplementedSecretSharingServiceServer) GetAggregate(context.Context, *GetAggregateRequest) (*GetAggregateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAggregate not implemented")
+}
+func (UnimplementedSecretSharingServiceServer) GetTriples(context.Context, *GetTriplesRequest) (*GetTriplesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTriples not implemented")
+}
+func (UnimplementedSecretSharingServiceServer) SendOpening(context.Context, *Opening) (*Ack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendOpening not implemented")
+}
+func (UnimplementedSecretSharingServiceServer) GetOpened(context.Context, *GetOpenedRequest) (*GetOpenedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOpened not implemented")
+}
+func (UnimplementedSecretSharingServiceServer) GetBitMasks(context.Context, *GetBitMasksRequest) (*GetBitMasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBitMasks not implemented")
+}
+func (UnimplementedSecretSharingServiceServer) GetBudget(context.Context, *GetBudgetRequest) (*GetBudgetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBudget not implemented")
+}
 func (UnimplementedSecretSharingServiceServer) mustEmbedUnimplementedSecretSharingServiceServer() {}
 
 // UnsafeSecretSharingServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -186,6 +364,186 @@ func _SecretSharingService_GetAddedOut_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SecretSharingService_GetOutShares_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOutSharesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretSharingServiceServer).GetOutShares(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SecretSharingService/GetOutShares",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretSharingServiceServer).GetOutShares(ctx, req.(*GetOutSharesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SecretSharingService_CreateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretSharingServiceServer).CreateSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SecretSharingService/CreateSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretSharingServiceServer).CreateSession(ctx, req.(*CreateSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SecretSharingService_CloseSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretSharingServiceServer).CloseSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SecretSharingService/CloseSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretSharingServiceServer).CloseSession(ctx, req.(*CloseSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SecretSharingService_PublishAggregate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Aggregate)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretSharingServiceServer).PublishAggregate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SecretSharingService/PublishAggregate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretSharingServiceServer).PublishAggregate(ctx, req.(*Aggregate))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SecretSharingService_GetAggregate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAggregateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretSharingServiceServer).GetAggregate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SecretSharingService/GetAggregate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretSharingServiceServer).GetAggregate(ctx, req.(*GetAggregateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SecretSharingService_GetTriples_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTriplesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretSharingServiceServer).GetTriples(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SecretSharingService/GetTriples",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretSharingServiceServer).GetTriples(ctx, req.(*GetTriplesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SecretSharingService_SendOpening_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Opening)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretSharingServiceServer).SendOpening(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SecretSharingService/SendOpening",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretSharingServiceServer).SendOpening(ctx, req.(*Opening))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SecretSharingService_GetOpened_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOpenedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretSharingServiceServer).GetOpened(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SecretSharingService/GetOpened",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretSharingServiceServer).GetOpened(ctx, req.(*GetOpenedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SecretSharingService_GetBitMasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBitMasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretSharingServiceServer).GetBitMasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SecretSharingService/GetBitMasks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretSharingServiceServer).GetBitMasks(ctx, req.(*GetBitMasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SecretSharingService_GetBudget_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBudgetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretSharingServiceServer).GetBudget(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SecretSharingService/GetBudget",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretSharingServiceServer).GetBudget(ctx, req.(*GetBudgetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SecretSharingService_ServiceDesc is the grpc.ServiceDesc for SecretSharingService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -209,6 +567,46 @@ var SecretSharingService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAddedOut",
 			Handler:    _SecretSharingService_GetAddedOut_Handler,
 		},
+		{
+			MethodName: "GetOutShares",
+			Handler:    _SecretSharingService_GetOutShares_Handler,
+		},
+		{
+			MethodName: "CreateSession",
+			Handler:    _SecretSharingService_CreateSession_Handler,
+		},
+		{
+			MethodName: "CloseSession",
+			Handler:    _SecretSharingService_CloseSession_Handler,
+		},
+		{
+			MethodName: "PublishAggregate",
+			Handler:    _SecretSharingService_PublishAggregate_Handler,
+		},
+		{
+			MethodName: "GetAggregate",
+			Handler:    _SecretSharingService_GetAggregate_Handler,
+		},
+		{
+			MethodName: "GetTriples",
+			Handler:    _SecretSharingService_GetTriples_Handler,
+		},
+		{
+			MethodName: "SendOpening",
+			Handler:    _SecretSharingService_SendOpening_Handler,
+		},
+		{
+			MethodName: "GetOpened",
+			Handler:    _SecretSharingService_GetOpened_Handler,
+		},
+		{
+			MethodName: "GetBitMasks",
+			Handler:    _SecretSharingService_GetBitMasks_Handler,
+		},
+		{
+			MethodName: "GetBudget",
+			Handler:    _SecretSharingService_GetBudget_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "secure_aggregation.proto",
@@ -0,0 +1,36 @@
+// Command aggregator runs the standalone aggregation server the parties send
+// their shares through. SIGINT or SIGTERM stop it gracefully.
+//
+//	go run ./cmd/aggregator -config config/hospital.yaml
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"hospital/internal/config"
+	"hospital/internal/server"
+)
+
+func main() {
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	cfg, err := config.Load(fs, os.Args[1:])
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+
+	srv, err := server.New(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := srv.Start(ctx); err != nil {
+		log.Fatal(err)
+	}
+}
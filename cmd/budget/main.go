@@ -0,0 +1,48 @@
+// Command budget prints the privacy budget every participant spent per
+// dataset, as recorded in the aggregation server's ledger. It connects with
+// the certificate of -identity, which must be listed as an admin in the
+// policy to see other participants. -participant and -dataset limit the
+// output to one participant and dataset:
+//
+//	go run ./cmd/budget -identity Admin
+//	go run ./cmd/budget -identity Alice -participant Alice -dataset icu
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"hospital/internal/client"
+	"hospital/internal/config"
+)
+
+func main() {
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	participant := fs.String("participant", "", "only show this participant")
+
+	cfg, err := config.Load(fs, os.Args[1:])
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	if cfg.Identity == "" {
+		log.Fatal("-identity is required")
+	}
+
+	conns := client.NewConnManager(cfg)
+	defer conns.Close()
+	budgets, err := client.Budgets(context.Background(), cfg, conns, *participant, cfg.Privacy.Dataset)
+	if err != nil {
+		log.Fatalf("could not query the budget ledger: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PARTICIPANT\tDATASET\tSESSIONS\tEPSILON\tDELTA")
+	for _, b := range budgets {
+		fmt.Fprintf(w, "%s\t%q\t%d\t%v / %v\t%v / %v\n", b.Participant, b.Dataset, b.Sessions, b.Epsilon, b.MaxEpsilon, b.Delta, b.MaxDelta)
+	}
+	w.Flush()
+}
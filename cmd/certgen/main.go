@@ -0,0 +1,105 @@
+// Command certgen bootstraps the certificates for a deployment without
+// openssl: a CA, a server certificate and one client certificate per
+// participant.
+//
+//	go run ./cmd/certgen -participants Alice,Bob,Charlie
+//
+// With -renew the existing CA in -dir is kept and only the server and
+// participant certificates are issued again, so new hospitals can be added
+// and expiring certificates replaced without touching the others.
+package main
+
+import (
+	"crypto/x509/pkix"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"hospital/internal/pki"
+)
+
+func main() {
+	dir := flag.String("dir", "cert", "directory to write the certificates to")
+	participants := flag.String("participants", "Alice,Bob,Charlie", "comma separated participants to issue certificates for")
+	serverSANs := flag.String("server-san", "localhost,*.localhost,127.0.0.1", "comma separated host names and IPs of the server, empty to skip the server certificate")
+	caDays := flag.Int("ca-days", 365, "validity of a new CA in days")
+	days := flag.Int("days", 60, "validity of the issued certificates in days")
+	renew := flag.Bool("renew", false, "reuse the existing CA instead of creating a new one")
+	flag.Parse()
+
+	names := splitList(*participants)
+	if err := pki.CheckNames(names); err != nil {
+		log.Fatalf("invalid participants: %v", err)
+	}
+
+	caCert, caKey := filepath.Join(*dir, "ca-cert.pem"), filepath.Join(*dir, "ca-key.pem")
+	if err := os.MkdirAll(*dir, 0755); err != nil {
+		log.Fatalf("could not create %s: %v", *dir, err)
+	}
+
+	var ca *pki.CA
+	var err error
+	if *renew {
+		if ca, err = pki.LoadCA(caCert, caKey); err != nil {
+			log.Fatalf("could not load CA: %v", err)
+		}
+		log.Printf("Using existing CA %q, valid until %s", ca.Cert.Subject.CommonName, ca.Cert.NotAfter.Format(time.DateOnly))
+	} else {
+		if _, err := os.Stat(caCert); err == nil {
+			log.Fatalf("%s already exists, use -renew to keep it or remove it to start over", caCert)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			log.Fatalf("could not check %s: %v", caCert, err)
+		}
+
+		subject := pkix.Name{Country: []string{"DK"}, Organization: []string{"Hospital"}, CommonName: "Hospital Aggregation CA"}
+		if ca, err = pki.NewCA(subject, inDays(*caDays)); err != nil {
+			log.Fatalf("could not create CA: %v", err)
+		}
+		if err := ca.WriteCA(caCert, caKey); err != nil {
+			log.Fatalf("could not write CA: %v", err)
+		}
+		log.Printf("Created CA %s", caCert)
+	}
+
+	if sans := splitList(*serverSANs); len(sans) > 0 {
+		der, key, err := ca.IssueServer(sans, inDays(*days))
+		if err != nil {
+			log.Fatalf("could not issue server certificate: %v", err)
+		}
+		certFile, keyFile := pki.Files(*dir, "server")
+		if err := pki.WriteKeyPair(certFile, keyFile, der, key); err != nil {
+			log.Fatalf("could not write server certificate: %v", err)
+		}
+		log.Printf("Issued server certificate %s for %v", certFile, sans)
+	}
+
+	for _, name := range names {
+		der, key, err := ca.IssueParticipant(name, inDays(*days))
+		if err != nil {
+			log.Fatalf("could not issue certificate for %s: %v", name, err)
+		}
+		certFile, keyFile := pki.Files(*dir, name)
+		if err := pki.WriteKeyPair(certFile, keyFile, der, key); err != nil {
+			log.Fatalf("could not write certificate for %s: %v", name, err)
+		}
+		log.Printf("Issued participant certificate %s for %s", certFile, name)
+	}
+}
+
+func inDays(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
+
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
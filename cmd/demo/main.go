@@ -0,0 +1,174 @@
+// Command demo simulates a whole aggregation in one process: it starts the
+// server and runs every participant as a goroutine. In p2p mode it also starts
+// every participant's endpoint, on the addresses from -peers or on
+// localhost:50061 and up. Use cmd/aggregator and cmd/party to run them as
+// separate processes.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+
+	"hospital/internal/client"
+	"hospital/internal/config"
+	"hospital/internal/server"
+	"hospital/internal/stats"
+)
+
+func main() {
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	participantList := fs.String("participants", "Alice=30,Bob=300,Charlie=30", "comma separated name=input pairs, inputs like 5.42:1 are vectors")
+	threshold := fs.Int("threshold", 0, "use Shamir sharing with this threshold of at least 2, 0 for additive sharing")
+	op := fs.String("op", "sum", "computation to run: sum aggregates the inputs element-wise, exceeds checks whether the aggregate exceeds -limit; count, mean, variance and histogram treat every participant's inputs as its rows")
+	limit := fs.String("limit", "100", "limits for -op exceeds, one per input element like 100:5")
+	edgeList := fs.String("edges", "0:50:100:500", "bin edges for -op histogram, like 0:50:100")
+
+	cfg, err := config.Load(fs, os.Args[1:])
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+
+	participants, err := client.ParseParticipants(*participantList)
+	if err != nil {
+		log.Fatalf("invalid participants: %v", err)
+	}
+	if *threshold != 0 && (*threshold < 2 || *threshold > len(participants)) {
+		log.Fatalf("-threshold %d out of range [2, %d], or 0 for additive sharing", *threshold, len(participants))
+	}
+	if (*op == "variance" || *op == "exceeds") && *threshold != 0 {
+		log.Fatalf("-op %s needs additive sharing, drop -threshold", *op)
+	}
+	if (*op == "variance" || *op == "exceeds") && cfg.Privacy.Dataset != "" {
+		log.Fatalf("-op %s releases exact results, which no privacy budget covers; drop -dataset", *op)
+	}
+	limits, err := client.ParseValues(*limit)
+	if err != nil {
+		log.Fatalf("invalid limit: %v", err)
+	}
+	edges, err := client.ParseValues(*edgeList)
+	if err != nil {
+		log.Fatalf("invalid edges: %v", err)
+	}
+
+	// The servers run in the background until the aggregation is over
+	ctx, stopServers := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	start := func(srv *server.Server, err error) {
+		if err != nil {
+			log.Fatal(err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := srv.Start(ctx); err != nil {
+				log.Printf("server failed: %v", err)
+			}
+		}()
+	}
+
+	start(server.New(cfg))
+	if cfg.Mode == config.ModeP2P {
+		for _, peerCfg := range endpointConfigs(cfg, participants) {
+			start(server.NewPeer(peerCfg))
+		}
+	}
+
+	results, err := run(ctx, cfg, *op, participants, *threshold, limits, edges)
+	stopServers()
+	wg.Wait()
+	if err != nil {
+		log.Fatalf("%s failed: %v", *op, err)
+	}
+	for _, p := range participants {
+		log.Printf("%s computed %s %s", p.Name, *op, results[p.Name])
+	}
+}
+
+// run computes op for all participants within one process and returns every
+// participant's result, formatted for printing.
+func run(ctx context.Context, cfg *config.Config, op string, participants []client.Participant, threshold int, limits, edges []float64) (map[string]string, error) {
+	results := make(map[string]string)
+	switch op {
+	case "sum":
+		outputs, err := client.StartClient(ctx, cfg, participants, threshold)
+		for name, out := range outputs {
+			results[name] = client.FormatValues(out)
+		}
+		return results, err
+	case "variance":
+		variances, err := client.StartVariance(ctx, cfg, participants)
+		for name, v := range variances {
+			results[name] = fmt.Sprint(v)
+		}
+		return results, err
+	case "exceeds":
+		exceeds, err := client.StartExceeds(ctx, cfg, participants, limits)
+		for name, e := range exceeds {
+			results[name] = fmt.Sprint(e)
+		}
+		return results, err
+	}
+
+	// The statistics all produce a vector of numbers
+	session := client.Session{Threshold: threshold, Privacy: cfg.Privacy.Params, Dataset: cfg.Privacy.Dataset}
+	var statistic func(context.Context, *client.Party, []float64) ([]float64, error)
+	switch op {
+	case "count":
+		statistic = func(ctx context.Context, p *client.Party, rows []float64) ([]float64, error) {
+			n, err := stats.Count(ctx, p, rows)
+			return []float64{float64(n)}, err
+		}
+	case "mean":
+		session.Length = stats.MeanLength
+		statistic = func(ctx context.Context, p *client.Party, rows []float64) ([]float64, error) {
+			mean, err := stats.Mean(ctx, p, rows)
+			return []float64{mean}, err
+		}
+	case "histogram":
+		session.Length = len(edges) - 1
+		statistic = func(ctx context.Context, p *client.Party, rows []float64) ([]float64, error) {
+			counts, err := stats.Histogram(ctx, p, rows, edges)
+			out := make([]float64, len(counts))
+			for i, c := range counts {
+				out[i] = float64(c)
+			}
+			return out, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op)
+	}
+	outputs, err := client.RunParties(ctx, cfg, participants, session, func(p *client.Party, pt client.Participant) ([]float64, error) {
+		return statistic(ctx, p, pt.Inputs)
+	})
+	for name, out := range outputs {
+		results[name] = client.FormatValues(out)
+	}
+	return results, err
+}
+
+// endpointConfigs returns the configuration of every participant's endpoint
+// for p2p mode.
+func endpointConfigs(cfg *config.Config, participants []client.Participant) []*config.Config {
+	if cfg.Peers == nil {
+		cfg.Peers = make(map[string]string)
+	}
+	for i, p := range participants {
+		if _, ok := cfg.Peers[p.Name]; !ok {
+			cfg.Peers[p.Name] = fmt.Sprintf("localhost:%d", 50061+i)
+		}
+	}
+
+	var configs []*config.Config
+	for _, p := range participants {
+		peerCfg := *cfg
+		peerCfg.Identity = p.Name
+		peerCfg.ListenAddr = cfg.Peers[p.Name]
+		peerCfg.StateFile = "" // the endpoints would share the server's log
+		configs = append(configs, &peerCfg)
+	}
+	return configs
+}
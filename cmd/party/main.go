@@ -0,0 +1,170 @@
+// Command party runs a single hospital in its own process, with its own
+// identity and private input. All parties of an aggregation are started with
+// the same -session, -participants and -threshold:
+//
+//	go run ./cmd/party -identity Alice -input 5.42 -session demo -participants Alice,Bob,Charlie
+//
+// With -mode p2p the party also serves its own endpoint on -listen-addr and
+// exchanges shares with the endpoints given by -peers; the aggregation server
+// only receives the result:
+//
+//	go run ./cmd/party -mode p2p -listen-addr :50061 -peers Alice=localhost:50061,Bob=localhost:50062,Charlie=localhost:50063 \
+//		-identity Alice -input 30 -session demo -participants Alice,Bob,Charlie
+//
+// With -op count, mean, variance or histogram the input is the party's rows,
+// and every party prints the statistic of all parties' rows together. With
+// noise, -op mean needs a -sensitivity of at least 1+max|row|, as one row
+// changes both the count and the sum. With -op exceeds every party prints
+// whether the aggregate exceeds -limit, without learning the aggregate.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"hospital/internal/client"
+	"hospital/internal/config"
+	"hospital/internal/dp"
+	"hospital/internal/server"
+	"hospital/internal/stats"
+)
+
+func main() {
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	input := fs.String("input", "0", "private input of this participant, a vector like 5.42:1:0 aggregates element-wise; rounded to the fixed-point -scale")
+	session := fs.String("session", "", "session id shared by all participants")
+	participantList := fs.String("participants", "", "comma separated names of all participants, in the same order for everyone")
+	threshold := fs.Int("threshold", 0, "use Shamir sharing with this threshold of at least 2, 0 for additive sharing")
+	op := fs.String("op", "sum", "computation to run: sum aggregates the inputs element-wise, exceeds checks whether the aggregate exceeds -limit; count, mean, variance and histogram treat the inputs as rows")
+	limit := fs.String("limit", "100", "limits for -op exceeds, one per input element like 100:5")
+	edgeList := fs.String("edges", "0:50:100:500", "bin edges for -op histogram, like 0:50:100")
+
+	cfg, err := config.Load(fs, os.Args[1:])
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	if cfg.Identity == "" || *session == "" {
+		log.Fatal("-identity and -session are required")
+	}
+
+	names, err := client.ParseNames(*participantList)
+	if err != nil {
+		log.Fatalf("invalid participants: %v", err)
+	}
+	if *threshold != 0 && (*threshold < 2 || *threshold > len(names)) {
+		log.Fatalf("-threshold %d out of range [2, %d], or 0 for additive sharing", *threshold, len(names))
+	}
+	if (*op == "variance" || *op == "exceeds") && *threshold != 0 {
+		log.Fatalf("-op %s needs additive sharing, drop -threshold", *op)
+	}
+	inputs, err := client.ParseValues(*input)
+	if err != nil {
+		log.Fatalf("invalid input: %v", err)
+	}
+	length := len(inputs)
+	limits, err := client.ParseValues(*limit)
+	if err != nil {
+		log.Fatalf("invalid limit: %v", err)
+	}
+	edges, err := client.ParseValues(*edgeList)
+	if err != nil {
+		log.Fatalf("invalid edges: %v", err)
+	}
+	switch *op {
+	case "sum", "exceeds":
+	case "count":
+		length = 1
+	case "mean":
+		length = stats.MeanLength
+	case "variance":
+		length = client.CovarianceLength
+	case "histogram":
+		length = len(edges) - 1
+	default:
+		log.Fatalf("unknown -op %q, expected sum, exceeds, count, mean, variance or histogram", *op)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if cfg.Mode == config.ModeP2P {
+		// Peers deliver our shares here until the aggregation is over
+		endpoint, err := server.NewPeer(cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		endpointCtx, stopEndpoint := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			if err := endpoint.Start(endpointCtx); err != nil {
+				log.Printf("endpoint failed: %v", err)
+			}
+		}()
+		defer func() {
+			stopEndpoint()
+			<-done
+		}()
+	}
+
+	// Noise only protects the outputs of the aggregation rounds
+	privacy := cfg.Privacy.Params
+	if *op == "variance" || *op == "exceeds" {
+		if cfg.Privacy.Dataset != "" {
+			log.Fatalf("-op %s releases exact results, which no privacy budget covers; drop -dataset", *op)
+		}
+		privacy = dp.Params{}
+	}
+
+	conns := client.NewConnManager(cfg)
+	party, err := client.NewParty(cfg, conns, cfg.Identity, client.Session{ID: *session, Participants: names, Threshold: *threshold, Length: length, Privacy: privacy, Dataset: cfg.Privacy.Dataset})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	result, err := run(ctx, party, *op, inputs, limits, edges)
+	// The server drops the session once every party closed it, so the same
+	// -session can be run again afterwards
+	if err := party.CloseSession(context.WithoutCancel(ctx)); err != nil {
+		log.Printf("could not close session %s: %v", *session, err)
+	}
+	conns.Close()
+	if err != nil {
+		var clientErr *client.Error
+		if errors.As(err, &clientErr) && clientErr.Temporary() {
+			log.Fatalf("aggregation did not finish in time, try again: %v", err)
+		}
+		log.Fatalf("aggregation failed: %v", err)
+	}
+	fmt.Println(result)
+}
+
+// run computes op with the party's inputs and returns the result, formatted
+// for printing.
+func run(ctx context.Context, party *client.Party, op string, inputs, limits, edges []float64) (string, error) {
+	switch op {
+	case "exceeds":
+		exceeds, err := party.Exceeds(ctx, inputs, limits)
+		return fmt.Sprint(exceeds), err
+	case "count":
+		n, err := stats.Count(ctx, party, inputs)
+		return fmt.Sprint(n), err
+	case "mean":
+		mean, err := stats.Mean(ctx, party, inputs)
+		return fmt.Sprint(mean), err
+	case "variance":
+		variance, err := party.Variance(ctx, inputs)
+		return fmt.Sprint(variance), err
+	case "histogram":
+		counts, err := stats.Histogram(ctx, party, inputs, edges)
+		return fmt.Sprint(counts), err
+	}
+	result, err := party.ContributeFloats(ctx, inputs)
+	return client.FormatValues(result), err
+}
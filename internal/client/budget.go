@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+
+	pb "hospital/api"
+	"hospital/internal/config"
+)
+
+// Budget is the privacy budget a participant spent on a dataset, as recorded
+// in the server's ledger.
+type Budget struct {
+	Participant string
+	Dataset     string
+	Epsilon     float64 // spent so far
+	Delta       float64
+	Sessions    int     // sessions that spent it
+	MaxEpsilon  float64 // limit across all sessions
+	MaxDelta    float64
+}
+
+// Budgets queries the server's privacy budget ledger as cfg.Identity, limited
+// to participant and dataset unless they are empty. Admins may query every
+// participant, others only themselves.
+func Budgets(ctx context.Context, cfg *config.Config, conns *ConnManager, participant, dataset string) ([]Budget, error) {
+	conn, err := conns.Get(cfg.ServerAddr, cfg.Identity)
+	if err != nil {
+		return nil, &Error{Op: "GetBudget", Err: err}
+	}
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeouts.Request)
+	defer cancel()
+
+	r, err := pb.NewSecretSharingServiceClient(conn).GetBudget(ctx, &pb.GetBudgetRequest{Participant: participant, Dataset: dataset})
+	if err != nil {
+		return nil, &Error{Op: "GetBudget", Err: err}
+	}
+	budgets := make([]Budget, len(r.Budgets))
+	for i, b := range r.Budgets {
+		budgets[i] = Budget{
+			Participant: b.Participant,
+			Dataset:     b.Dataset,
+			Epsilon:     b.Epsilon,
+			Delta:       b.Delta,
+			Sessions:    int(b.Sessions),
+			MaxEpsilon:  b.MaxEpsilon,
+			MaxDelta:    b.MaxDelta,
+		}
+	}
+	return budgets, nil
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestParseNames(t *testing.T) {
+	names, err := ParseNames(" Alice, Bob ,Charlie")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Alice", "Bob", "Charlie"}; !slices.Equal(names, want) {
+		t.Errorf("names %q, want %q", names, want)
+	}
+	for _, list := range []string{"", "Alice", "Alice,Alice", "Alice,,Bob"} {
+		if _, err := ParseNames(list); err == nil {
+			t.Errorf("ParseNames(%q) succeeded", list)
+		}
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	for _, values := range [][]float64{{5.42}, {1, 0, -1.5}, {0.1, 1e300, -1e-300}} {
+		list := FormatValues(values)
+		parsed, err := ParseValues(list)
+		if err != nil {
+			t.Fatalf("ParseValues(%q): %v", list, err)
+		}
+		if !slices.Equal(parsed, values) {
+			t.Errorf("ParseValues(%q) = %v, want %v", list, parsed, values)
+		}
+	}
+	if values, err := ParseValues(" 1 : 2"); err != nil || !slices.Equal(values, []float64{1, 2}) {
+		t.Errorf("values %v, %v, want [1 2]", values, err)
+	}
+	for _, list := range []string{"", "1:", "1,2", "x"} {
+		if _, err := ParseValues(list); err == nil {
+			t.Errorf("ParseValues(%q) succeeded", list)
+		}
+	}
+}
+
+func TestParseParticipants(t *testing.T) {
+	participants, err := ParseParticipants("Alice=5.42:1, Bob=300:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Participant{{"Alice", []float64{5.42, 1}}, {"Bob", []float64{300, 0}}}
+	if !reflect.DeepEqual(participants, want) {
+		t.Errorf("participants %v, want %v", participants, want)
+	}
+	for _, list := range []string{"Alice=1", "Alice=1,Alice=2", "Alice=1,=2", "Alice=1,Bob", "Alice=1,Bob=x"} {
+		if _, err := ParseParticipants(list); err == nil {
+			t.Errorf("ParseParticipants(%q) succeeded", list)
+		}
+	}
+}
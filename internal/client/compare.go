@@ -0,0 +1,280 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+
+	pb "hospital/api"
+)
+
+// bitMask is a party's additive share of a random field element r and of each
+// of its bits, least significant first.
+type bitMask struct {
+	value *big.Int
+	bits  []*big.Int
+}
+
+// Exceeds reports for every element whether the aggregate of all
+// participants' values exceeds the corresponding limit, e.g. whether the
+// hospitals together saw more than 100 adverse events. The aggregate itself
+// stays secret: only the comparison bits are opened. The session must share
+// vectors of len(values) elements with additive sharing.
+func (p *Party) Exceeds(ctx context.Context, values, limits []float64) ([]bool, error) {
+	if len(values) != len(limits) {
+		return nil, &Error{Op: "Exceeds", Err: fmt.Errorf("got %d values and %d limits", len(values), len(limits))}
+	}
+	enc, field := p.cfg.Encoding(), p.cfg.Field()
+	encodedValues, err := enc.EncodeVector(values)
+	if err != nil {
+		return nil, &Error{Op: "Encode", Err: err}
+	}
+	encodedLimits, err := enc.EncodeVector(limits)
+	if err != nil {
+		return nil, &Error{Op: "Encode", Err: err}
+	}
+
+	shared, err := p.Share(ctx, encodedValues)
+	if err != nil {
+		return nil, err
+	}
+	// The aggregate exceeds the limit exactly when limit - aggregate < 0
+	differences := make([]*big.Int, len(shared))
+	for i, x := range shared {
+		differences[i] = field.Sub(p.constant(encodedLimits[i]), x)
+	}
+	negative, err := p.IsNegative(ctx, differences)
+	if err != nil {
+		return nil, err
+	}
+	opened, err := p.Open(ctx, negative)
+	if err != nil {
+		return nil, err
+	}
+
+	exceeds := make([]bool, len(opened))
+	for i, bit := range opened {
+		if !bit.IsInt64() || bit.Int64() > 1 {
+			return nil, &Error{Op: "Exceeds", Err: fmt.Errorf("comparison %d opened to %v, not a bit", i, bit)}
+		}
+		exceeds[i] = bit.Int64() == 1
+	}
+	log.Printf("Client - %s comparison result: %v", p.name, exceeds)
+	return exceeds, nil
+}
+
+// LessThan returns the party's shares of the bits [x[i] < y[i]] for two shared
+// vectors, see IsNegative.
+func (p *Party) LessThan(ctx context.Context, x, y []*big.Int) ([]*big.Int, error) {
+	if len(x) != len(y) {
+		return nil, &Error{Op: "LessThan", Err: fmt.Errorf("cannot compare vectors of length %d and %d", len(x), len(y))}
+	}
+	field := p.cfg.Field()
+	differences := make([]*big.Int, len(x))
+	for i := range x {
+		differences[i] = field.Sub(x[i], y[i])
+	}
+	return p.IsNegative(ctx, differences)
+}
+
+// IsNegative returns the party's shares of a bit per element of the shared
+// vector z: 1 if the element is negative, i.e. above modulus/2, and 0
+// otherwise. Nothing else about z is revealed. All parties must call it in
+// the same order as Multiply and Open.
+//
+// The modulus is odd, as NewField refuses 2, so z is negative exactly when 2z
+// wraps around, which makes 2z mod p odd. The least significant bit of a = 2z
+// is found with a bit mask r from the dealer: c = a + r is opened, and then
+//
+//	lsb(a) = lsb(c) XOR lsb(r) XOR [c < r]
+//
+// as a = c - r, or c - r + p when a + r wrapped around, which is when c < r.
+// [c < r] is computed from the public bits of c and the shared bits of r with
+// a comparison circuit of log2(bits) rounds of multiplication.
+func (p *Party) IsNegative(ctx context.Context, z []*big.Int) ([]*big.Int, error) {
+	if p.session.Threshold != 0 {
+		return nil, &Error{Op: "Compare", Err: fmt.Errorf("comparison needs additive sharing")}
+	}
+	masks, err := p.getBitMasks(ctx, len(z))
+	if err != nil {
+		return nil, err
+	}
+
+	field := p.cfg.Field()
+	masked := make([]*big.Int, len(z))
+	for i := range z {
+		masked[i] = field.Add(field.Add(z[i], z[i]), masks[i].value)
+	}
+	c, err := p.open(ctx, "cmp", masked)
+	if err != nil {
+		return nil, err
+	}
+
+	wraps, err := p.lessThanBits(ctx, c, masks)
+	if err != nil {
+		return nil, err
+	}
+
+	// lsb(c) XOR lsb(r) is linear as lsb(c) is public; XOR with the shared
+	// wrap bit w is u + w - 2uw
+	u := make([]*big.Int, len(z))
+	for i, m := range masks {
+		u[i] = p.xorPublic(c[i].Bit(0), m.bits[0])
+	}
+	uw, err := p.Multiply(ctx, u, wraps)
+	if err != nil {
+		return nil, err
+	}
+	negative := make([]*big.Int, len(z))
+	for i := range z {
+		negative[i] = field.Sub(field.Add(u[i], wraps[i]), field.Add(uw[i], uw[i]))
+	}
+	return negative, nil
+}
+
+// lessThanBits returns the party's shares of [c[i] < r[i]] for public c and
+// the masks' shared bits of r. With e[j] = [c_j = r_j] and P[j] the product of
+// e above and including bit j,
+//
+//	[c < r] = sum over the bits j with c_j = 0 of r_j * P[j+1]
+//
+// as the highest bit where c and r differ decides, and at most one term is 1.
+func (p *Party) lessThanBits(ctx context.Context, c []*big.Int, masks []bitMask) ([]*big.Int, error) {
+	field := p.cfg.Field()
+	bits := field.Bits()
+	one := p.constant(big.NewInt(1))
+
+	equal := make([][]*big.Int, len(c))
+	for i, m := range masks {
+		equal[i] = make([]*big.Int, bits)
+		for j, r := range m.bits {
+			// [c_j = r_j] is r_j when c_j is 1 and 1 - r_j otherwise
+			equal[i][j] = p.xorPublic(1-c[i].Bit(j), r)
+		}
+	}
+	suffix, err := p.suffixProducts(ctx, equal)
+	if err != nil {
+		return nil, err
+	}
+
+	var xs, ys []*big.Int
+	var owner []int
+	for i, m := range masks {
+		for j := range bits {
+			if c[i].Bit(j) == 1 {
+				continue
+			}
+			above := one
+			if j+1 < bits {
+				above = suffix[i][j+1]
+			}
+			xs, ys, owner = append(xs, m.bits[j]), append(ys, above), append(owner, i)
+		}
+	}
+	less := field.Zeros(len(c))
+	if len(xs) == 0 {
+		return less, nil
+	}
+	terms, err := p.Multiply(ctx, xs, ys)
+	if err != nil {
+		return nil, err
+	}
+	for k, term := range terms {
+		less[owner[k]] = field.Add(less[owner[k]], term)
+	}
+	return less, nil
+}
+
+// suffixProducts returns for every vector v of shared values the shares of
+// the products v[j] * v[j+1] * ... for every j. It doubles the span of the
+// products each round, so all vectors together take log2(len(v)) rounds of
+// multiplication.
+func (p *Party) suffixProducts(ctx context.Context, vs [][]*big.Int) ([][]*big.Int, error) {
+	products := make([][]*big.Int, len(vs))
+	width := 0
+	for i, v := range vs {
+		products[i] = append([]*big.Int(nil), v...)
+		width = max(width, len(v))
+	}
+
+	type index struct{ i, j int }
+	for span := 1; span < width; span *= 2 {
+		var xs, ys []*big.Int
+		var targets []index
+		for i, v := range products {
+			for j := 0; j+span < len(v); j++ {
+				xs, ys, targets = append(xs, v[j]), append(ys, v[j+span]), append(targets, index{i, j})
+			}
+		}
+		result, err := p.Multiply(ctx, xs, ys)
+		if err != nil {
+			return nil, err
+		}
+		for k, t := range targets {
+			products[t.i][t.j] = result[k]
+		}
+	}
+	return products, nil
+}
+
+// constant returns the party's additive share of the public value v: v for the
+// first participant and 0 for the others.
+func (p *Party) constant(v *big.Int) *big.Int {
+	if p.self == 0 {
+		return p.cfg.Field().Mod(v)
+	}
+	return new(big.Int)
+}
+
+// xorPublic returns the party's share of the public bit b XOR the shared bit
+// x: x when b is 0 and 1 - x otherwise.
+func (p *Party) xorPublic(b uint, x *big.Int) *big.Int {
+	if b == 0 {
+		return x
+	}
+	return p.cfg.Field().Sub(p.constant(big.NewInt(1)), x)
+}
+
+// getBitMasks fetches the party's shares of the next n bit masks from the
+// central server, which deals them.
+func (p *Party) getBitMasks(ctx context.Context, n int) ([]bitMask, error) {
+	if err := p.joinDealer(ctx); err != nil {
+		return nil, err
+	}
+
+	offset := p.masks.Add(uint64(n)) - uint64(n)
+	req := &pb.GetBitMasksRequest{Participant: p.name, Session: p.session.ID, Offset: uint32(offset), Count: uint32(n)}
+
+	var response *pb.GetBitMasksResponse
+	err := p.retry(ctx, "GetBitMasks", sendRetryCodes, func(ctx context.Context) (err error) {
+		ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeouts.Request)
+		defer cancel()
+		response, err = p.client.GetBitMasks(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, &Error{Op: "GetBitMasks", Err: err}
+	}
+	if len(response.Masks) != n {
+		return nil, &Error{Op: "GetBitMasks", Err: fmt.Errorf("got %d masks, asked for %d", len(response.Masks), n)}
+	}
+
+	field := p.cfg.Field()
+	masks := make([]bitMask, n)
+	for i, m := range response.Masks {
+		value, err := field.FromBytes(m.Value)
+		if err != nil {
+			return nil, &Error{Op: "GetBitMasks", Err: err}
+		}
+		bits, err := field.Unmarshal(m.Bits)
+		if err != nil {
+			return nil, &Error{Op: "GetBitMasks", Err: err}
+		}
+		if len(bits) != field.Bits() {
+			return nil, &Error{Op: "GetBitMasks", Err: fmt.Errorf("mask has %d bits, the field %d", len(bits), field.Bits())}
+		}
+		masks[i] = bitMask{value: value, bits: bits}
+	}
+	return masks, nil
+}
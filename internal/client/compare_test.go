@@ -0,0 +1,33 @@
+package client
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"hospital/internal/testutil"
+)
+
+func TestExceeds(t *testing.T) {
+	cfg := testutil.Config(t, "Alice", "Bob", "Charlie")
+	stop := testutil.StartServer(t, cfg)
+	defer stop()
+
+	// The aggregates are 360, 10 and -5
+	participants := []Participant{
+		{"Alice", []float64{30, 4, -10}},
+		{"Bob", []float64{300, 3, 2.5}},
+		{"Charlie", []float64{30, 3, 2.5}},
+	}
+	limits := []float64{100, 10, -5.5}
+	results, err := StartExceeds(context.Background(), cfg, participants, limits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []bool{true, false, true}
+	for _, pt := range participants {
+		if !slices.Equal(results[pt.Name], want) {
+			t.Errorf("%s computed %v, want %v", pt.Name, results[pt.Name], want)
+		}
+	}
+}
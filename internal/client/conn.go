@@ -0,0 +1,217 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"hospital/internal/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+)
+
+// ErrClosed is returned by a ConnManager that was already closed.
+var ErrClosed = errors.New("connection manager closed")
+
+// ConnManager holds one connection per address and identity. A gRPC
+// connection multiplexes concurrent calls, so all parties and goroutines
+// acting as the same identity share it. Connections are kept alive with
+// pings, so a dead peer is noticed even while no call is running, and only
+// handed out once they are ready. Close shuts every connection down.
+type ConnManager struct {
+	cfg *config.Config
+
+	mu     sync.Mutex
+	conns  map[connKey]*grpc.ClientConn
+	closed bool
+
+	ctx     context.Context // cancelled on Close to stop the watchers
+	cancel  context.CancelFunc
+	watches sync.WaitGroup
+}
+
+type connKey struct {
+	addr     string
+	identity string
+	peer     string // participant expected at addr, empty for the server
+}
+
+// NewConnManager returns an empty manager; connections are dialed on first
+// use.
+func NewConnManager(cfg *config.Config) *ConnManager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ConnManager{
+		cfg:    cfg,
+		conns:  make(map[connKey]*grpc.ClientConn),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Get returns the connection to addr authenticated as identity, creating it
+// on first use. It waits up to the wait timeout for the connection to be
+// ready, so an unreachable server is reported here rather than left for the
+// first call to find. Errors setting up the connection, e.g. a missing client
+// certificate, are returned right away.
+func (m *ConnManager) Get(addr, identity string) (*grpc.ClientConn, error) {
+	return m.get(connKey{addr: addr, identity: identity})
+}
+
+// GetPeer is like Get for the endpoint of participant peer in peer-to-peer
+// mode. The endpoint must present peer's certificate, so a wrong address
+// cannot make the party hand its shares to someone else.
+func (m *ConnManager) GetPeer(addr, identity, peer string) (*grpc.ClientConn, error) {
+	return m.get(connKey{addr: addr, identity: identity, peer: peer})
+}
+
+func (m *ConnManager) get(key connKey) (*grpc.ClientConn, error) {
+	conn, err := m.conn(key)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to %s as %s: %w", key.addr, key.identity, err)
+	}
+	if err := m.waitReady(conn); err != nil {
+		return nil, fmt.Errorf("could not connect to %s as %s: %w", key.addr, key.identity, err)
+	}
+	return conn, nil
+}
+
+// conn returns the connection for key, dialing it if there is none yet.
+func (m *ConnManager) conn(key connKey) (*grpc.ClientConn, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil, ErrClosed
+	}
+	if conn, ok := m.conns[key]; ok {
+		return conn, nil
+	}
+
+	conn, err := createTLSConnection(m.cfg, key.addr, key.identity, key.peer)
+	if err != nil {
+		return nil, err
+	}
+	m.conns[key] = conn
+
+	m.watches.Add(1)
+	go m.watch(key, conn)
+	return conn, nil
+}
+
+// waitReady waits until conn is ready, for up to the wait timeout. A
+// connection that failed is redialed right away instead of after its
+// reconnect backoff.
+func (m *ConnManager) waitReady(conn *grpc.ClientConn) error {
+	ctx, cancel := context.WithTimeout(m.ctx, m.cfg.Timeouts.Wait)
+	defer cancel()
+
+	state := conn.GetState()
+	if state == connectivity.TransientFailure {
+		conn.ResetConnectBackoff()
+	}
+	conn.Connect()
+	for state != connectivity.Ready {
+		if state == connectivity.Shutdown {
+			return ErrClosed
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			if m.ctx.Err() != nil {
+				return ErrClosed
+			}
+			return fmt.Errorf("connection still %s after %v", strings.ToLower(state.String()), m.cfg.Timeouts.Wait)
+		}
+		state = conn.GetState()
+	}
+	return nil
+}
+
+// watch logs the connection's state changes until it is closed, so dropped
+// and recovered connections show up in the logs.
+func (m *ConnManager) watch(key connKey, conn *grpc.ClientConn) {
+	defer m.watches.Done()
+
+	state := conn.GetState()
+	for conn.WaitForStateChange(m.ctx, state) {
+		state = conn.GetState()
+		switch state {
+		case connectivity.Shutdown:
+			return
+		case connectivity.TransientFailure:
+			log.Printf("Client - connection to %s as %s failed, reconnecting", key.addr, key.identity)
+		case connectivity.Ready:
+			log.Printf("Client - connected to %s as %s", key.addr, key.identity)
+		}
+	}
+}
+
+// Close closes all connections. Calls in flight fail with Canceled.
+func (m *ConnManager) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	conns := m.conns
+	m.conns = nil
+	m.mu.Unlock()
+
+	m.cancel()
+	var errs []error
+	for key, conn := range conns {
+		if err := conn.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("closing connection to %s as %s: %w", key.addr, key.identity, err))
+		}
+	}
+	m.watches.Wait()
+	return errors.Join(errs...)
+}
+
+func createTLSConnection(cfg *config.Config, addr, identity, peer string) (*grpc.ClientConn, error) {
+	// Load certificate of the CA who signed server's certificate
+	pemServerCA, err := os.ReadFile(cfg.TLS.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(pemServerCA) {
+		return nil, fmt.Errorf("failed to add server CA's certificate")
+	}
+
+	// Load the participant's own certificate, the server checks it against
+	// the name the participant sends shares as
+	clientCert, err := tls.LoadX509KeyPair(cfg.TLS.KeyPair(identity))
+	if err != nil {
+		return nil, fmt.Errorf("could not load client certificate for %s: %w", identity, err)
+	}
+
+	// Participant certificates carry the name as DNS SAN, so a peer endpoint
+	// is verified against the participant rather than its address
+	creds := credentials.NewTLS(&tls.Config{
+		RootCAs:      certPool,
+		Certificates: []tls.Certificate{clientCert},
+		ServerName:   peer,
+	})
+
+	// Reconnect as eagerly as the calls are retried, so a restarted server
+	// is found before the retries give up
+	reconnect := grpc.ConnectParams{
+		Backoff:           backoff.Config{BaseDelay: cfg.Retry.InitialBackoff, Multiplier: 1.6, Jitter: 0.2, MaxDelay: cfg.Retry.MaxBackoff},
+		MinConnectTimeout: cfg.Timeouts.Request,
+	}
+	// Pings find connections whose peer went away without closing them,
+	// e.g. after a crash or a network failure, and make them reconnect
+	alive := keepalive.ClientParameters{Time: cfg.Timeouts.Keepalive, Timeout: cfg.Timeouts.Request, PermitWithoutStream: true}
+	return grpc.NewClient(addr, grpc.WithTransportCredentials(creds), grpc.WithConnectParams(reconnect), grpc.WithKeepaliveParams(alive))
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hospital/internal/testutil"
+
+	"google.golang.org/grpc/connectivity"
+)
+
+func TestConnManagerReuse(t *testing.T) {
+	cfg := testutil.Config(t, "Alice", "Bob")
+	stop := testutil.StartServer(t, cfg)
+	defer stop()
+
+	m := NewConnManager(cfg)
+	get := func(identity string) any {
+		t.Helper()
+		conn, err := m.Get(cfg.ServerAddr, identity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state := conn.GetState(); state != connectivity.Ready {
+			t.Errorf("got a connection in state %v", state)
+		}
+		return conn
+	}
+
+	alice := get("Alice")
+	if get("Alice") != alice {
+		t.Error("second connection for the same address and identity")
+	}
+	if get("Bob") == alice {
+		t.Error("Bob shares Alice's connection")
+	}
+	if _, err := m.Get(cfg.ServerAddr, "Mallory"); err == nil {
+		t.Error("connected without a certificate")
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Get(cfg.ServerAddr, "Alice"); !errors.Is(err, ErrClosed) {
+		t.Errorf("got %v after Close, want ErrClosed", err)
+	}
+}
+
+func TestConnManagerRedial(t *testing.T) {
+	cfg := testutil.Config(t, "Alice")
+	cfg.Timeouts.Wait = 500 * time.Millisecond
+	m := NewConnManager(cfg)
+	defer m.Close()
+
+	// An unreachable server is reported rather than handed out
+	if _, err := m.Get(cfg.ServerAddr, "Alice"); err == nil {
+		t.Fatal("got a connection to a server that is not running")
+	}
+
+	stop := testutil.StartServer(t, cfg)
+	conn, err := m.Get(cfg.ServerAddr, "Alice")
+	if err != nil {
+		t.Fatalf("no connection once the server runs: %v", err)
+	}
+	stop()
+	if _, err := m.Get(cfg.ServerAddr, "Alice"); err == nil {
+		t.Fatal("got a connection to a stopped server")
+	}
+
+	// The failed connection is redialed when needed again
+	stop = testutil.StartServer(t, cfg)
+	defer stop()
+	again, err := m.Get(cfg.ServerAddr, "Alice")
+	if err != nil {
+		t.Fatalf("no connection after the server restarted: %v", err)
+	}
+	if again != conn {
+		t.Error("redialing replaced the connection parties hold")
+	}
+}
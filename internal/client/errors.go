@@ -0,0 +1,48 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrNotParticipant is returned when a party's identity is not one of the
+// session's participants.
+var ErrNotParticipant = errors.New("not a participant of the session")
+
+// Error describes a failed step of the protocol. Callers can inspect it with
+// errors.As to decide whether to retry, report or abort.
+type Error struct {
+	Op   string // protocol step, e.g. "SendShare"
+	Peer string // participant the step was about, if any
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Peer != "" {
+		return fmt.Sprintf("%s %s: %v", e.Op, e.Peer, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", e.Op, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Code returns the gRPC status code of the underlying error, codes.Unknown
+// for errors that did not come from an RPC.
+func (e *Error) Code() codes.Code {
+	return status.Code(e.Err)
+}
+
+// Temporary reports whether the step may succeed when tried again, e.g.
+// because the server was unreachable or peers were slow to send their shares.
+func (e *Error) Temporary() bool {
+	switch e.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
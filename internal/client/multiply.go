@@ -0,0 +1,244 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+
+	pb "hospital/api"
+)
+
+// CovarianceLength is the vector length of sessions used for Covariance and
+// Variance.
+const CovarianceLength = 4
+
+// triple is a party's additive share of a Beaver triple: random a and b and
+// c = a * b.
+type triple struct {
+	a, b, c *big.Int
+}
+
+// Multiply returns the party's share of the element-wise product of two
+// shared vectors, such as those returned by Share. It consumes one Beaver
+// triple per element: every party opens its shares of x - a and y - b, which
+// reveals nothing to the parties as a and b are random, and corrects its
+// share of c with the opened values. The server deals the triples and knows
+// a and b, so in central mode, where the openings pass through it, it learns
+// x and y. All parties must call Multiply and Open in the same order.
+// Multiplication needs additive sharing.
+func (p *Party) Multiply(ctx context.Context, x, y []*big.Int) ([]*big.Int, error) {
+	if p.session.Threshold != 0 {
+		return nil, &Error{Op: "Multiply", Err: fmt.Errorf("multiplication needs additive sharing")}
+	}
+	if len(x) != len(y) {
+		return nil, &Error{Op: "Multiply", Err: fmt.Errorf("cannot multiply vectors of length %d and %d", len(x), len(y))}
+	}
+
+	triples, err := p.getTriples(ctx, len(x))
+	if err != nil {
+		return nil, err
+	}
+
+	field := p.cfg.Field()
+	masked := make([]*big.Int, 2*len(x))
+	for i, t := range triples {
+		masked[i] = field.Sub(x[i], t.a)
+		masked[len(x)+i] = field.Sub(y[i], t.b)
+	}
+	opened, err := p.open(ctx, "mul", masked)
+	if err != nil {
+		return nil, err
+	}
+
+	// x*y = c + d*b + e*a + d*e with d = x-a and e = y-b. The public d*e is
+	// added by the first participant only.
+	z := make([]*big.Int, len(x))
+	for i, t := range triples {
+		d, e := opened[i], opened[len(x)+i]
+		z[i] = field.Add(t.c, field.Add(field.Mul(d, t.b), field.Mul(e, t.a)))
+		if p.self == 0 {
+			z[i] = field.Add(z[i], field.Mul(d, e))
+		}
+	}
+	return z, nil
+}
+
+// Dot returns the party's share of the dot product of two shared vectors.
+func (p *Party) Dot(ctx context.Context, x, y []*big.Int) (*big.Int, error) {
+	products, err := p.Multiply(ctx, x, y)
+	if err != nil {
+		return nil, err
+	}
+	field := p.cfg.Field()
+	sum := new(big.Int)
+	for _, v := range products {
+		sum = field.Add(sum, v)
+	}
+	return sum, nil
+}
+
+// Open reveals a shared vector to every participant: each party sends its
+// share to the others and adds up all shares.
+func (p *Party) Open(ctx context.Context, x []*big.Int) ([]*big.Int, error) {
+	return p.open(ctx, "open", x)
+}
+
+// Covariance returns the population covariance of the pairs (xs[i], ys[i]) of
+// all participants together. The session must share vectors of
+// CovarianceLength elements. Every party shares its count and its sums of x, y
+// and x*y; only the total count and N*Σxy - Σx*Σy are opened, not the sums
+// themselves.
+func (p *Party) Covariance(ctx context.Context, xs, ys []float64) (float64, error) {
+	if len(xs) != len(ys) {
+		return 0, &Error{Op: "Covariance", Err: fmt.Errorf("got %d x and %d y values", len(xs), len(ys))}
+	}
+
+	// Σx and Σy are encoded with the fixed-point scale, Σxy with its square
+	enc, field := p.cfg.Encoding(), p.cfg.Field()
+	sumX, sumY, sumXY := new(big.Int), new(big.Int), new(big.Int)
+	for i := range xs {
+		x, err := enc.Encode(xs[i])
+		if err != nil {
+			return 0, &Error{Op: "Encode", Err: err}
+		}
+		y, err := enc.Encode(ys[i])
+		if err != nil {
+			return 0, &Error{Op: "Encode", Err: err}
+		}
+		x, y = field.Signed(x), field.Signed(y)
+		sumX.Add(sumX, x)
+		sumY.Add(sumY, y)
+		sumXY.Add(sumXY, new(big.Int).Mul(x, y))
+	}
+
+	// [N, Σx, Σy, Σxy]
+	shared, err := p.Share(ctx, []*big.Int{field.Int(int64(len(xs))), field.Mod(sumX), field.Mod(sumY), field.Mod(sumXY)})
+	if err != nil {
+		return 0, err
+	}
+	products, err := p.Multiply(ctx, []*big.Int{shared[0], shared[1]}, []*big.Int{shared[3], shared[2]})
+	if err != nil {
+		return 0, err
+	}
+	opened, err := p.Open(ctx, []*big.Int{field.Sub(products[0], products[1]), shared[0]})
+	if err != nil {
+		return 0, err
+	}
+
+	n := field.Signed(opened[1])
+	if n.Sign() <= 0 {
+		return 0, &Error{Op: "Covariance", Err: fmt.Errorf("no values")}
+	}
+	scale := big.NewInt(enc.Scale)
+	denominator := new(big.Int).Mul(n, n)
+	denominator.Mul(denominator, scale).Mul(denominator, scale)
+	cov, _ := new(big.Rat).SetFrac(field.Signed(opened[0]), denominator).Float64()
+	log.Printf("Client - %s covariance over %d values: %v", p.name, n, cov)
+	return cov, nil
+}
+
+// Variance returns the population variance of all participants' values
+// together, see Covariance.
+func (p *Party) Variance(ctx context.Context, xs []float64) (float64, error) {
+	return p.Covariance(ctx, xs, xs)
+}
+
+// joinDealer makes sure the central server, which deals triples and bit
+// masks, keeps the privacy budget ledger and records the published outputs,
+// knows the session. In peer-to-peer mode it otherwise never hears of it.
+func (p *Party) joinDealer(ctx context.Context) error {
+	if !p.p2p() {
+		return nil
+	}
+	_, err := p.createSession(ctx, p.client, p.session.ID)
+	return err
+}
+
+// getTriples fetches the party's shares of the next n Beaver triples from the
+// central server, which deals them.
+func (p *Party) getTriples(ctx context.Context, n int) ([]triple, error) {
+	if err := p.joinDealer(ctx); err != nil {
+		return nil, err
+	}
+
+	offset := p.triples.Add(uint64(n)) - uint64(n)
+	req := &pb.GetTriplesRequest{Participant: p.name, Session: p.session.ID, Offset: uint32(offset), Count: uint32(n)}
+
+	var response *pb.GetTriplesResponse
+	err := p.retry(ctx, "GetTriples", sendRetryCodes, func(ctx context.Context) (err error) {
+		ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeouts.Request)
+		defer cancel()
+		response, err = p.client.GetTriples(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, &Error{Op: "GetTriples", Err: err}
+	}
+	if len(response.Triples) != n {
+		return nil, &Error{Op: "GetTriples", Err: fmt.Errorf("got %d triples, asked for %d", len(response.Triples), n)}
+	}
+
+	field := p.cfg.Field()
+	triples := make([]triple, n)
+	for i, t := range response.Triples {
+		values, err := field.Unmarshal([][]byte{t.A, t.B, t.C})
+		if err != nil {
+			return nil, &Error{Op: "GetTriples", Err: err}
+		}
+		triples[i] = triple{a: values[0], b: values[1], c: values[2]}
+	}
+	return triples, nil
+}
+
+// open sends the party's share of a value to every other participant and
+// returns the sum of all shares. kind only makes the round names readable.
+func (p *Party) open(ctx context.Context, kind string, share []*big.Int) ([]*big.Int, error) {
+	field := p.cfg.Field()
+	round := fmt.Sprintf("%s-%d", kind, p.rounds.Add(1))
+
+	err := p.forEachPeer(ctx, func(ctx context.Context, i int, peer string) error {
+		return p.sendOpening(ctx, &pb.Opening{From: p.name, To: peer, Session: p.session.ID, Round: round, Values: field.Marshal(share)})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Client - Sending GetOpened request for participant %s, round %s", p.name, round)
+	var response *pb.GetOpenedResponse
+	err = p.retry(ctx, "GetOpened", waitRetryCodes, func(ctx context.Context) (err error) {
+		ctx, cancel := p.waitContext(ctx)
+		defer cancel()
+		response, err = p.endpoints[p.name].GetOpened(ctx, &pb.GetOpenedRequest{Participant: p.name, Session: p.session.ID, Round: round})
+		return err
+	})
+	if err != nil {
+		return nil, &Error{Op: "GetOpened", Err: err}
+	}
+	others, err := field.Unmarshal(response.Values)
+	if err != nil {
+		return nil, &Error{Op: "GetOpened", Err: err}
+	}
+	opened, err := field.AddVectors(share, others)
+	if err != nil {
+		return nil, &Error{Op: "GetOpened", Err: err}
+	}
+	return opened, nil
+}
+
+func (p *Party) sendOpening(ctx context.Context, o *pb.Opening) error {
+	o.Seq = p.seq.Add(1)
+
+	var r *pb.Ack
+	err := p.retry(ctx, "SendOpening", sendRetryCodes, func(ctx context.Context) (err error) {
+		ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeouts.Request)
+		defer cancel()
+		r, err = p.endpoints[o.To].SendOpening(ctx, o)
+		return err
+	})
+	if err != nil {
+		return &Error{Op: "SendOpening", Peer: o.To, Err: err}
+	}
+	log.Printf("Client - Acknowledgement: %s", r.GetMessage())
+	return nil
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"hospital/internal/testutil"
+)
+
+func TestVariance(t *testing.T) {
+	cfg := testutil.Config(t, "Alice", "Bob", "Charlie")
+	stop := testutil.StartServer(t, cfg)
+	defer stop()
+
+	participants := []Participant{
+		{"Alice", []float64{1, 2}},
+		{"Bob", []float64{3}},
+		{"Charlie", []float64{4, 5, 5}},
+	}
+	variances, err := StartVariance(context.Background(), cfg, participants)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Population variance of 1, 2, 3, 4, 5, 5
+	want := 80.0/6 - (20.0/6)*(20.0/6)
+	for _, pt := range participants {
+		if got := variances[pt.Name]; math.Abs(got-want) > 1e-6 {
+			t.Errorf("%s computed %v, want %v", pt.Name, got, want)
+		}
+	}
+}
@@ -0,0 +1,686 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	pb "hospital/api"
+	"hospital/internal/config"
+	"hospital/internal/dp"
+	"hospital/internal/sharing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Session describes an aggregation round. All parties of a round must use the
+// same participants, in the same order, the same threshold, the same length
+// and the same privacy parameters.
+type Session struct {
+	ID           string    // empty lets the server pick one on Join
+	Participants []string  // names of all participating hospitals
+	Threshold    int       // 0 for additive sharing, otherwise Shamir sharing with this threshold
+	Length       int       // elements every participant contributes, 0 for a single value
+	Privacy      dp.Params // differential privacy noise added to the output of ContributeVector
+	Dataset      string    // dataset the session queries, its noise is charged to the server's budget ledger
+}
+
+// length returns the number of elements every input has.
+func (s Session) length() int {
+	return max(s.Length, 1)
+}
+
+// Party is one hospital taking part in a session.
+type Party struct {
+	cfg     *config.Config
+	name    string
+	self    int // index of name in session.Participants
+	session Session
+
+	// client reaches the central server. In peer-to-peer mode it is only
+	// used to publish the aggregate.
+	client pb.SecretSharingServiceClient
+	// endpoints holds where the shares for each participant are delivered:
+	// the central server, or in peer-to-peer mode the participant's own
+	// endpoint.
+	endpoints map[string]pb.SecretSharingServiceClient
+
+	// seq numbers the party's messages. Together with the sender and the
+	// session it identifies a message, so the server drops retried
+	// duplicates.
+	seq atomic.Uint64
+	// triples and masks count the Beaver triples and bit masks the party
+	// used, rounds the values it opened. Parties calling Multiply, Open and
+	// the comparisons in the same order agree on all three.
+	triples atomic.Uint64
+	masks   atomic.Uint64
+	rounds  atomic.Uint64
+}
+
+// NewParty returns a party acting as name, which must be one of the session's
+// participants and hold a client certificate. The party talks to the server,
+// and in peer-to-peer mode to the endpoints in cfg.Peers, over connections
+// from conns, which stay open until conns is closed.
+func NewParty(cfg *config.Config, conns *ConnManager, name string, session Session) (*Party, error) {
+	self := slices.Index(session.Participants, name)
+	if self < 0 {
+		return nil, &Error{Op: "NewParty", Peer: name, Err: ErrNotParticipant}
+	}
+
+	conn, err := conns.Get(cfg.ServerAddr, name)
+	if err != nil {
+		return nil, &Error{Op: "NewParty", Peer: name, Err: err}
+	}
+	p := &Party{
+		cfg:       cfg,
+		name:      name,
+		self:      self,
+		session:   session,
+		client:    pb.NewSecretSharingServiceClient(conn),
+		endpoints: make(map[string]pb.SecretSharingServiceClient),
+	}
+
+	for _, peer := range session.Participants {
+		if cfg.Mode != config.ModeP2P {
+			p.endpoints[peer] = p.client
+			continue
+		}
+		addr, err := cfg.PeerAddr(peer)
+		if err != nil {
+			return nil, &Error{Op: "NewParty", Peer: peer, Err: err}
+		}
+		conn, err := conns.GetPeer(addr, name, peer)
+		if err != nil {
+			return nil, &Error{Op: "NewParty", Peer: peer, Err: err}
+		}
+		p.endpoints[peer] = pb.NewSecretSharingServiceClient(conn)
+	}
+	return p, nil
+}
+
+func (p *Party) p2p() bool {
+	return p.cfg.Mode == config.ModeP2P
+}
+
+// hosts returns the servers holding the session's state: the central
+// server, or in peer-to-peer mode every participant's endpoint, starting with
+// the party's own.
+func (p *Party) hosts() []pb.SecretSharingServiceClient {
+	if !p.p2p() {
+		return []pb.SecretSharingServiceClient{p.client}
+	}
+	hosts := []pb.SecretSharingServiceClient{p.endpoints[p.name]}
+	for _, peer := range p.session.Participants {
+		if peer != p.name {
+			hosts = append(hosts, p.endpoints[peer])
+		}
+	}
+	return hosts
+}
+
+// SessionID returns the id of the session, once known.
+func (p *Party) SessionID() string {
+	return p.session.ID
+}
+
+// Join creates the session on the server, or joins it if another party
+// already created it with the same setup, and returns its id. In peer-to-peer
+// mode the session is created on every participant's endpoint; the party's
+// own endpoint picks the id if none was given. A session with noise is then
+// also created on the central server, which keeps the privacy budget ledger
+// and charges every participant once they take part.
+func (p *Party) Join(ctx context.Context) (string, error) {
+	id := p.session.ID
+	for _, host := range p.hosts() {
+		var err error
+		if id, err = p.createSession(ctx, host, id); err != nil {
+			return "", err
+		}
+	}
+	p.session.ID = id
+	if p.session.Privacy.Enabled() {
+		if err := p.joinDealer(ctx); err != nil {
+			return "", err
+		}
+	}
+	log.Printf("Client - %s joined session %s", p.name, p.session.ID)
+	return p.session.ID, nil
+}
+
+// createSession creates the session with the given id on host, or lets host
+// pick the id if it is empty, and returns the id.
+func (p *Party) createSession(ctx context.Context, host pb.SecretSharingServiceClient, id string) (string, error) {
+	req := &pb.CreateSessionRequest{Session: id, Participants: p.session.Participants, Length: int32(p.session.Length), Dataset: p.session.Dataset}
+	if p.session.Threshold > 0 {
+		req.Scheme = pb.Scheme_SHAMIR
+		req.Threshold = int32(p.session.Threshold)
+	}
+	if privacy := p.session.Privacy; privacy.Enabled() {
+		req.Privacy = &pb.Privacy{Mechanism: mechanisms[privacy.Mechanism], Epsilon: privacy.Epsilon, Delta: privacy.Delta, Sensitivity: privacy.Sensitivity}
+	}
+
+	// Creating a session is idempotent once it has an id, so only then it is
+	// safe to retry
+	retryOn := sendRetryCodes
+	if id == "" {
+		retryOn = nil
+	}
+
+	var r *pb.CreateSessionResponse
+	err := p.retry(ctx, "CreateSession", retryOn, func(ctx context.Context) (err error) {
+		ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeouts.Request)
+		defer cancel()
+		r, err = host.CreateSession(ctx, req)
+		return err
+	})
+	if err != nil {
+		return "", &Error{Op: "CreateSession", Err: err}
+	}
+	return r.GetSession(), nil
+}
+
+// CloseSession leaves the session on the server, or in peer-to-peer mode on
+// every participant's endpoint and on the central server if it knows the
+// session. A server drops the session's state once every participant left,
+// so closing never cuts off the others still fetching their outputs.
+func (p *Party) CloseSession(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeouts.Request)
+	defer cancel()
+
+	var errs []error
+	for _, host := range p.hosts() {
+		if _, err := host.CloseSession(ctx, &pb.CloseSessionRequest{Session: p.session.ID}); err != nil {
+			errs = append(errs, &Error{Op: "CloseSession", Err: err})
+		}
+	}
+	if p.p2p() {
+		_, err := p.client.CloseSession(ctx, &pb.CloseSessionRequest{Session: p.session.ID})
+		if err != nil && status.Code(err) != codes.NotFound {
+			errs = append(errs, &Error{Op: "CloseSession", Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// mechanisms maps the noise of a session to the mechanism the server knows.
+var mechanisms = map[dp.Mechanism]pb.Mechanism{
+	dp.Laplace:  pb.Mechanism_LAPLACE,
+	dp.Gaussian: pb.Mechanism_GAUSSIAN,
+}
+
+// Contribute runs the protocol with the party's private value and returns the
+// aggregate of all participants' values. The session must share single
+// values.
+func (p *Party) Contribute(ctx context.Context, value int64) (int64, error) {
+	field := p.cfg.Field()
+	out, err := p.ContributeVector(ctx, []*big.Int{field.Int(value)})
+	if err != nil {
+		return 0, err
+	}
+	sum := field.Signed(out[0])
+	if !sum.IsInt64() {
+		return 0, &Error{Op: "Reconstruct", Err: fmt.Errorf("aggregate %v does not fit an int64", sum)}
+	}
+	return sum.Int64(), nil
+}
+
+// ContributeVector runs the protocol with the party's private vector and
+// returns the element-wise aggregate of all participants' vectors, both as
+// field elements. It splits every element into one share per participant,
+// keeps its own shares and sends the rest to the others, then combines what
+// it received into the final output. With a threshold the output only needs
+// threshold-1 out shares from the others, so the aggregation finishes even
+// when participants drop out after sharing.
+//
+// In peer-to-peer mode the shares go straight to the other participants'
+// endpoints and the output is published to the central server at the end.
+// With noise the party also joins the session on the central server, which
+// charges it to the party's privacy budget.
+//
+// With privacy parameters every party adds its share of the noise, so the
+// output is differentially private, also towards a single party subtracting
+// its own share but not towards colluding parties; see dp.Params.NoiseShare.
+// The sensitivity is in units of the values.
+func (p *Party) ContributeVector(ctx context.Context, values []*big.Int) ([]*big.Int, error) {
+	return p.contribute(ctx, values, 1)
+}
+
+// contribute is ContributeVector for values that encode 1 as unit, which the
+// noise is scaled by.
+func (p *Party) contribute(ctx context.Context, values []*big.Int, unit int64) ([]*big.Int, error) {
+	field := p.cfg.Field()
+	noise, err := p.noise(len(values), unit)
+	if err != nil {
+		return nil, err
+	}
+	// Shamir out shares are points of a polynomial, adding noise to them would
+	// not add up. The noise goes into the input instead.
+	if p.session.Threshold > 0 {
+		if values, err = field.AddVectors(values, noise); err != nil {
+			return nil, &Error{Op: "Noise", Err: err}
+		}
+	}
+
+	localOut, err := p.Share(ctx, values)
+	if err != nil {
+		return nil, err
+	}
+	if p.session.Threshold == 0 {
+		if localOut, err = field.AddVectors(localOut, noise); err != nil {
+			return nil, &Error{Op: "Noise", Err: err}
+		}
+	}
+
+	err = p.forEachPeer(ctx, func(ctx context.Context, i int, peer string) error {
+		return p.sendOutShare(ctx, &pb.ShareOut{Data: field.Marshal(localOut), From: p.name, To: peer, Session: p.session.ID})
+	})
+	if err != nil {
+		// A peer that already reconstructed its output from threshold-1 out
+		// shares may have shut its endpoint down. It does not need ours.
+		if !p.p2p() || p.session.Threshold == 0 {
+			return nil, err
+		}
+		log.Printf("Client - %s could not deliver all out shares: %v", p.name, err)
+	}
+
+	out, err := p.output(ctx, localOut)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Client - %s final output: %d", p.name, out)
+
+	if p.p2p() {
+		if err := p.publish(ctx, out); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// Share runs the first phase of the protocol only: it shares values among the
+// participants and returns the party's share of the element-wise aggregate,
+// which stays secret until the participants open it. Computations like
+// Multiply continue from there.
+func (p *Party) Share(ctx context.Context, values []*big.Int) ([]*big.Int, error) {
+	if len(values) != p.session.length() {
+		return nil, &Error{Op: "Share", Err: fmt.Errorf("got %d values, the session shares %d", len(values), p.session.length())}
+	}
+
+	// Joining is idempotent, so every party can make sure the session exists
+	if _, err := p.Join(ctx); err != nil {
+		return nil, err
+	}
+
+	field := p.cfg.Field()
+	shares, err := splitInput(field, values, len(p.session.Participants), p.session.Threshold)
+	if err != nil {
+		return nil, &Error{Op: "Share", Err: err}
+	}
+
+	err = p.forEachPeer(ctx, func(ctx context.Context, i int, peer string) error {
+		return p.sendShare(ctx, &pb.Share{Parts: field.Marshal(shares[i]), From: p.name, To: peer, Session: p.session.ID})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Compute local result
+	addedShares, err := p.addedShares(ctx)
+	if err != nil {
+		return nil, err
+	}
+	localOut, err := field.AddVectors(shares[p.self], addedShares)
+	if err != nil {
+		return nil, &Error{Op: "GetAddedShares", Err: err}
+	}
+	return localOut, nil
+}
+
+// publish hands the party's output to the central server, which besides the
+// setup of the session is the only thing it learns in peer-to-peer mode.
+func (p *Party) publish(ctx context.Context, out []*big.Int) error {
+	if err := p.joinDealer(ctx); err != nil {
+		return err
+	}
+	err := p.retry(ctx, "PublishAggregate", sendRetryCodes, func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeouts.Request)
+		defer cancel()
+		_, err := p.client.PublishAggregate(ctx, &pb.Aggregate{Session: p.session.ID, From: p.name, Values: p.cfg.Field().Marshal(out)})
+		return err
+	})
+	if err != nil {
+		return &Error{Op: "PublishAggregate", Err: err}
+	}
+	return nil
+}
+
+// ContributeFloat is Contribute for real values. value is encoded with the
+// configured fixed-point scale and the aggregate decoded again. In
+// peer-to-peer mode the published aggregate is the encoded field element.
+func (p *Party) ContributeFloat(ctx context.Context, value float64) (float64, error) {
+	out, err := p.ContributeFloats(ctx, []float64{value})
+	if err != nil {
+		return 0, err
+	}
+	return out[0], nil
+}
+
+// ContributeFloats is ContributeVector for real values, see ContributeFloat.
+func (p *Party) ContributeFloats(ctx context.Context, values []float64) ([]float64, error) {
+	enc := p.cfg.Encoding()
+	encoded, err := enc.EncodeVector(values)
+	if err != nil {
+		return nil, &Error{Op: "Encode", Err: err}
+	}
+	out, err := p.contribute(ctx, encoded, enc.Scale)
+	if err != nil {
+		return nil, err
+	}
+	return enc.DecodeVector(out), nil
+}
+
+// noise returns the party's share of the session's noise for length values,
+// as field elements.
+func (p *Party) noise(length int, unit int64) ([]*big.Int, error) {
+	field := p.cfg.Field()
+	shares, err := p.session.Privacy.NoiseShare(length, len(p.session.Participants), unit)
+	if err != nil {
+		return nil, &Error{Op: "Noise", Err: err}
+	}
+	noise := make([]*big.Int, length)
+	for i, x := range shares {
+		noise[i] = field.Int(x)
+	}
+	return noise, nil
+}
+
+// output combines the party's own out share with the others' into the
+// aggregate.
+func (p *Party) output(ctx context.Context, localOut []*big.Int) ([]*big.Int, error) {
+	field := p.cfg.Field()
+	if p.session.Threshold == 0 {
+		addedOut, err := p.addedOut(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out, err := field.AddVectors(localOut, addedOut)
+		if err != nil {
+			return nil, &Error{Op: "GetAddedOut", Err: err}
+		}
+		return out, nil
+	}
+
+	// Shamir shares are evaluated at the participant's index + 1
+	points := []sharing.VectorPoint{{X: int64(p.self + 1), Y: localOut}}
+	outs, err := p.outShares(ctx, p.session.Threshold-1)
+	if err != nil {
+		return nil, err
+	}
+	for _, share := range outs {
+		data, err := field.Unmarshal(share.Data)
+		if err != nil {
+			return nil, &Error{Op: "GetOutShares", Peer: share.From, Err: err}
+		}
+		points = append(points, sharing.VectorPoint{X: int64(slices.Index(p.session.Participants, share.From) + 1), Y: data})
+	}
+	out, err := field.ShamirReconstructVector(points)
+	if err != nil {
+		return nil, &Error{Op: "Reconstruct", Err: err}
+	}
+	return out, nil
+}
+
+// forEachPeer calls fn concurrently for every other participant. The first
+// failure cancels the remaining calls; all failures are returned.
+func (p *Party) forEachPeer(ctx context.Context, fn func(ctx context.Context, i int, peer string) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(p.session.Participants))
+	for i, peer := range p.session.Participants {
+		if i == p.self {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if errs[i] = fn(ctx, i, peer); errs[i] != nil {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (p *Party) sendShare(ctx context.Context, share *pb.Share) error {
+	share.Seq = p.seq.Add(1)
+
+	var r *pb.Ack
+	err := p.retry(ctx, "SendShare", sendRetryCodes, func(ctx context.Context) (err error) {
+		ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeouts.Request)
+		defer cancel()
+		r, err = p.endpoints[share.To].SendShare(ctx, share)
+		return err
+	})
+	if err != nil {
+		return &Error{Op: "SendShare", Peer: share.To, Err: err}
+	}
+	log.Printf("Client - Acknowledgement: %s", r.GetMessage())
+	return nil
+}
+
+func (p *Party) sendOutShare(ctx context.Context, share *pb.ShareOut) error {
+	share.Seq = p.seq.Add(1)
+
+	var r *pb.Ack
+	err := p.retry(ctx, "SendShareOut", sendRetryCodes, func(ctx context.Context) (err error) {
+		ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeouts.Request)
+		defer cancel()
+		r, err = p.endpoints[share.To].SendShareOut(ctx, share)
+		return err
+	})
+	if err != nil {
+		return &Error{Op: "SendShareOut", Peer: share.To, Err: err}
+	}
+	log.Printf("Client - Acknowledgement: %s", r.GetMessage())
+	return nil
+}
+
+// The Get* calls go to where the party's own shares are delivered. They block
+// on the server until all shares arrived, so they get the server's wait
+// timeout on top of the request timeout.
+func (p *Party) waitContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, p.cfg.Timeouts.Wait+p.cfg.Timeouts.Request)
+}
+
+func (p *Party) addedShares(ctx context.Context) ([]*big.Int, error) {
+	log.Printf("Client - Sending GetAddedShares request for participant %s", p.name)
+
+	var response *pb.GetAddedSharesResponse
+	err := p.retry(ctx, "GetAddedShares", waitRetryCodes, func(ctx context.Context) (err error) {
+		ctx, cancel := p.waitContext(ctx)
+		defer cancel()
+		response, err = p.endpoints[p.name].GetAddedShares(ctx, &pb.GetAddedSharesRequest{Participant: p.name, Session: p.session.ID})
+		return err
+	})
+	if err != nil {
+		return nil, &Error{Op: "GetAddedShares", Err: err}
+	}
+	addedShares, err := p.cfg.Field().Unmarshal(response.AddedShares)
+	if err != nil {
+		return nil, &Error{Op: "GetAddedShares", Err: err}
+	}
+	return addedShares, nil
+}
+
+func (p *Party) addedOut(ctx context.Context) ([]*big.Int, error) {
+	log.Printf("Client - Sending GetAddedOut request for participant %s", p.name)
+
+	var response *pb.GetAddedOutResponse
+	err := p.retry(ctx, "GetAddedOut", waitRetryCodes, func(ctx context.Context) (err error) {
+		ctx, cancel := p.waitContext(ctx)
+		defer cancel()
+		response, err = p.endpoints[p.name].GetAddedOut(ctx, &pb.GetAddedOutRequest{Participant: p.name, Session: p.session.ID})
+		return err
+	})
+	if err != nil {
+		return nil, &Error{Op: "GetAddedOut", Err: err}
+	}
+	addedOut, err := p.cfg.Field().Unmarshal(response.AddedOut)
+	if err != nil {
+		return nil, &Error{Op: "GetAddedOut", Err: err}
+	}
+	return addedOut, nil
+}
+
+func (p *Party) outShares(ctx context.Context, minimum int) ([]*pb.ShareOut, error) {
+	log.Printf("Client - Sending GetOutShares request for participant %s", p.name)
+
+	var response *pb.GetOutSharesResponse
+	err := p.retry(ctx, "GetOutShares", waitRetryCodes, func(ctx context.Context) (err error) {
+		ctx, cancel := p.waitContext(ctx)
+		defer cancel()
+		response, err = p.endpoints[p.name].GetOutShares(ctx, &pb.GetOutSharesRequest{Participant: p.name, Session: p.session.ID, Minimum: int32(minimum)})
+		return err
+	})
+	if err != nil {
+		return nil, &Error{Op: "GetOutShares", Err: err}
+	}
+	return response.Shares, nil
+}
+
+// splitInput shares every element of values among n participants,
+// additively when threshold is 0 and with Shamir sharing otherwise. Share i
+// belongs to participant i.
+func splitInput(field *sharing.Field, values []*big.Int, n, threshold int) ([][]*big.Int, error) {
+	if threshold == 0 {
+		return field.ShareVector(values, n)
+	}
+
+	points, err := field.ShamirShareVector(values, threshold, n)
+	if err != nil {
+		return nil, err
+	}
+	shares := make([][]*big.Int, n)
+	for i, pt := range points {
+		shares[i] = pt.Y
+	}
+	return shares, nil
+}
+
+// StartClient runs the aggregation for all participants within one process
+// and returns every participant's output.
+func StartClient(ctx context.Context, cfg *config.Config, participants []Participant, threshold int) (map[string][]float64, error) {
+	if len(participants) == 0 {
+		return nil, fmt.Errorf("no participants")
+	}
+	for _, pt := range participants[1:] {
+		if len(pt.Inputs) != len(participants[0].Inputs) {
+			return nil, fmt.Errorf("%s has %d inputs, %s has %d", pt.Name, len(pt.Inputs), participants[0].Name, len(participants[0].Inputs))
+		}
+	}
+	session := Session{Threshold: threshold, Length: len(participants[0].Inputs), Privacy: cfg.Privacy.Params, Dataset: cfg.Privacy.Dataset}
+	return RunParties(ctx, cfg, participants, session, func(party *Party, pt Participant) ([]float64, error) {
+		return party.ContributeFloats(ctx, pt.Inputs)
+	})
+}
+
+// StartVariance computes the variance of all participants' inputs together
+// within one process and returns every participant's result.
+func StartVariance(ctx context.Context, cfg *config.Config, participants []Participant) (map[string]float64, error) {
+	outputs, err := RunParties(ctx, cfg, participants, Session{Length: CovarianceLength, Dataset: cfg.Privacy.Dataset}, func(party *Party, pt Participant) ([]float64, error) {
+		variance, err := party.Variance(ctx, pt.Inputs)
+		return []float64{variance}, err
+	})
+	variances := make(map[string]float64)
+	for name, out := range outputs {
+		variances[name] = out[0]
+	}
+	return variances, err
+}
+
+// StartExceeds checks within one process whether the aggregate of all
+// participants' inputs exceeds limits, element by element, and returns every
+// participant's result.
+func StartExceeds(ctx context.Context, cfg *config.Config, participants []Participant, limits []float64) (map[string][]bool, error) {
+	results := make(map[string][]bool)
+	var mu sync.Mutex
+	_, err := RunParties(ctx, cfg, participants, Session{Length: len(limits), Dataset: cfg.Privacy.Dataset}, func(party *Party, pt Participant) ([]float64, error) {
+		exceeds, err := party.Exceeds(ctx, pt.Inputs, limits)
+		mu.Lock()
+		results[pt.Name] = exceeds
+		mu.Unlock()
+		return nil, err
+	})
+	return results, err
+}
+
+// RunParties sets up session for the participants within one process and runs
+// fn for every participant as a separate goroutine, collecting their outputs.
+// session.Participants is filled in from participants.
+func RunParties(ctx context.Context, cfg *config.Config, participants []Participant, session Session, fn func(*Party, Participant) ([]float64, error)) (map[string][]float64, error) {
+	if len(participants) == 0 {
+		return nil, fmt.Errorf("no participants")
+	}
+	session.Participants = nil
+	for _, pt := range participants {
+		session.Participants = append(session.Participants, pt.Name)
+	}
+
+	conns := NewConnManager(cfg)
+	defer conns.Close()
+
+	// The first participant sets up the session on behalf of everyone
+	coordinator, err := NewParty(cfg, conns, participants[0].Name, session)
+	if err != nil {
+		return nil, err
+	}
+	if session.ID, err = coordinator.Join(ctx); err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	outputs := make(map[string][]float64)
+	errs := make([]error, len(participants))
+
+	// Start each party as a separate goroutine
+	for i, pt := range participants {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			party, err := NewParty(cfg, conns, pt.Name, session)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			defer func() {
+				if err := party.CloseSession(context.WithoutCancel(ctx)); err != nil {
+					log.Printf("Client - %s could not close session %s: %v", pt.Name, session.ID, err)
+				}
+			}()
+
+			out, err := fn(party, pt)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", pt.Name, err)
+				return
+			}
+			mu.Lock()
+			outputs[pt.Name] = out
+			mu.Unlock()
+		}()
+	}
+
+	// Wait for all parties to complete
+	wg.Wait()
+	log.Println("Client has finished.")
+	return outputs, errors.Join(errs...)
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"hospital/internal/config"
+	"hospital/internal/testutil"
+)
+
+func TestRerunSession(t *testing.T) {
+	cfg := testutil.Config(t, "Alice", "Bob", "Charlie")
+	stop := testutil.StartServer(t, cfg)
+	defer stop()
+
+	// Every party closes the session when done, so the same id starts afresh
+	for _, input := range []float64{1, 10} {
+		participants := []Participant{{"Alice", []float64{input}}, {"Bob", []float64{input}}, {"Charlie", []float64{input}}}
+		outputs, err := RunParties(context.Background(), cfg, participants, Session{ID: "rerun"}, func(party *Party, pt Participant) ([]float64, error) {
+			return party.ContributeFloats(context.Background(), pt.Inputs)
+		})
+		if err != nil {
+			t.Fatalf("input %v: %v", input, err)
+		}
+		for name, out := range outputs {
+			if out[0] != 3*input {
+				t.Errorf("%s computed %v, want %v", name, out[0], 3*input)
+			}
+		}
+	}
+}
+
+func TestPeerToPeer(t *testing.T) {
+	names := []string{"Alice", "Bob", "Charlie"}
+	cfg := testutil.Config(t, names...)
+	cfg.Mode = config.ModeP2P
+	cfg.Peers = make(map[string]string)
+	for _, name := range names {
+		cfg.Peers[name] = testutil.FreeAddr(t)
+	}
+	stop := testutil.StartServer(t, cfg)
+	defer stop()
+	for _, name := range names {
+		peerCfg := *cfg
+		peerCfg.Identity = name
+		peerCfg.ListenAddr = cfg.Peers[name]
+		peerCfg.StateFile = ""
+		stop := testutil.StartEndpoint(t, &peerCfg)
+		defer stop()
+	}
+
+	// The central server only sets up the session and takes the published
+	// outputs, which it accepts from its participants alone
+	participants := []Participant{{"Alice", []float64{1}}, {"Bob", []float64{2}}, {"Charlie", []float64{3}}}
+	outputs, err := StartClient(context.Background(), cfg, participants, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		if out := outputs[name]; len(out) != 1 || out[0] != 6 {
+			t.Errorf("%s computed %v, want [6]", name, out)
+		}
+	}
+}
+
+func TestNoParticipants(t *testing.T) {
+	cfg := config.Default()
+	if _, err := StartClient(context.Background(), cfg, nil, 0); err == nil {
+		t.Error("StartClient without participants succeeded")
+	}
+	if _, err := StartVariance(context.Background(), cfg, nil); err == nil {
+		t.Error("StartVariance without participants succeeded")
+	}
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"hospital/internal/storage"
+	"hospital/internal/testutil"
+)
+
+// waitForRecords polls the server's log at path until it holds n records of
+// the given kind.
+func waitForRecords(t *testing.T, path string, kind storage.Kind, n int) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			t.Fatal(err)
+		}
+		var count int
+		// A last line without a newline is still being written
+		for _, line := range bytes.SplitAfter(data, []byte{'\n'}) {
+			var rec storage.Record
+			if bytes.HasSuffix(line, []byte{'\n'}) && json.Unmarshal(line, &rec) == nil && rec.Kind == kind {
+				count++
+			}
+		}
+		if count >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d %s records in %s, want %d", count, kind, path, n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPartiesOutlastServerRestart(t *testing.T) {
+	names := []string{"Alice", "Bob", "Charlie"}
+	inputs := map[string]float64{"Alice": 30, "Bob": 300, "Charlie": 30}
+	cfg := testutil.Config(t, names...)
+	stop := testutil.StartServer(t, cfg)
+
+	conns := NewConnManager(cfg)
+	defer conns.Close()
+	ctx := context.Background()
+	session := Session{ID: "restart", Participants: names}
+
+	var wg sync.WaitGroup
+	results := make(map[string][]float64)
+	errs := make(map[string]error)
+	var mu sync.Mutex
+	contribute := func(name string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			party, err := NewParty(cfg, conns, name, session)
+			if err == nil {
+				_, err = party.Join(ctx)
+			}
+			var out []float64
+			if err == nil {
+				out, err = party.ContributeFloats(ctx, []float64{inputs[name]})
+			}
+			mu.Lock()
+			results[name], errs[name] = out, err
+			mu.Unlock()
+		}()
+	}
+
+	// Alice and Bob deliver their shares and wait for Charlie's when the
+	// server goes down, for longer than a handful of quick retries lasts
+	contribute("Alice")
+	contribute("Bob")
+	waitForRecords(t, cfg.StateFile, storage.KindShare, 4)
+	stop()
+	time.Sleep(time.Second)
+
+	stop = testutil.StartServer(t, cfg)
+	defer stop()
+	contribute("Charlie")
+	wg.Wait()
+
+	for _, name := range names {
+		if errs[name] != nil {
+			t.Errorf("%s failed: %v", name, errs[name])
+		} else if len(results[name]) != 1 || results[name][0] != 360 {
+			t.Errorf("%s computed %v, want [360]", name, results[name])
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"log"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// sendRetryCodes are retried for messages: the server deduplicates them,
+	// so a retry after a lost acknowledgement does no harm.
+	sendRetryCodes = []codes.Code{codes.Unavailable, codes.DeadlineExceeded}
+	// waitRetryCodes are retried for the blocking Get* calls. Their
+	// DeadlineExceeded means peers did not deliver in time, which waiting the
+	// whole wait timeout again rarely fixes. Aborted means the server shut
+	// down while waiting and resumes the session once it is back.
+	waitRetryCodes = []codes.Code{codes.Unavailable, codes.Aborted}
+)
+
+// retry calls fn until it succeeds, fails with a code not in retryOn, runs
+// out of time or attempts, or ctx is done. Failures are retried for up to the
+// wait timeout after the first one, long enough for a crashed server to
+// restart and restore its sessions, and at most Retry.Attempts times unless
+// that is 0. Between attempts it waits a random time below an exponentially
+// growing backoff ("full jitter"), so parties that failed together do not
+// retry in lockstep.
+func (p *Party) retry(ctx context.Context, op string, retryOn []codes.Code, fn func(ctx context.Context) error) error {
+	backoff := p.cfg.Retry.InitialBackoff
+	var deadline time.Time
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || !slices.Contains(retryOn, status.Code(err)) || attempt == p.cfg.Retry.Attempts {
+			return err
+		}
+		if attempt == 1 {
+			deadline = time.Now().Add(p.cfg.Timeouts.Wait)
+		}
+
+		wait := min(rand.N(backoff)+1, time.Until(deadline))
+		if wait <= 0 {
+			return err
+		}
+		log.Printf("Client - %s failed (attempt %d), retrying in %v: %v", op, attempt, wait, err)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+		backoff = min(2*backoff, p.cfg.Retry.MaxBackoff)
+	}
+}
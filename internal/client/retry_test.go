@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hospital/internal/config"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// retryParty returns a party that only knows enough to retry.
+func retryParty(attempts int, wait time.Duration) *Party {
+	cfg := config.Default()
+	cfg.Retry = config.RetryConfig{Attempts: attempts, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+	cfg.Timeouts.Wait = wait
+	return &Party{cfg: cfg}
+}
+
+// failing returns a call that fails with code the first n times.
+func failing(code codes.Code, n int, calls *int) func(context.Context) error {
+	return func(context.Context) error {
+		*calls++
+		if *calls <= n {
+			return status.Error(code, "failed")
+		}
+		return nil
+	}
+}
+
+func TestRetryOutlastsOutage(t *testing.T) {
+	p := retryParty(0, time.Second)
+	var calls int
+	// Far more failures than any fixed number of attempts would allow
+	if err := p.retry(context.Background(), "Test", sendRetryCodes, failing(codes.Unavailable, 50, &calls)); err != nil {
+		t.Fatalf("retry gave up within the wait timeout: %v", err)
+	}
+	if calls != 51 {
+		t.Errorf("fn called %d times, want 51", calls)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	t.Run("code", func(t *testing.T) {
+		var calls int
+		err := retryParty(0, time.Second).retry(context.Background(), "Test", sendRetryCodes, failing(codes.InvalidArgument, 5, &calls))
+		if status.Code(err) != codes.InvalidArgument || calls != 1 {
+			t.Errorf("got %v after %d calls, want InvalidArgument after 1", err, calls)
+		}
+	})
+	t.Run("attempts", func(t *testing.T) {
+		var calls int
+		err := retryParty(3, time.Second).retry(context.Background(), "Test", sendRetryCodes, failing(codes.Unavailable, 5, &calls))
+		if status.Code(err) != codes.Unavailable || calls != 3 {
+			t.Errorf("got %v after %d calls, want Unavailable after 3", err, calls)
+		}
+	})
+	t.Run("time", func(t *testing.T) {
+		var calls int
+		start := time.Now()
+		err := retryParty(0, 50*time.Millisecond).retry(context.Background(), "Test", sendRetryCodes, failing(codes.Unavailable, 1<<30, &calls))
+		if status.Code(err) != codes.Unavailable {
+			t.Errorf("got %v, want Unavailable", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("retried for %v with a wait timeout of 50ms", elapsed)
+		}
+	})
+	t.Run("context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var calls int
+		err := retryParty(0, time.Minute).retry(ctx, "Test", sendRetryCodes, failing(codes.Unavailable, 1<<30, &calls))
+		if status.Code(err) != codes.Unavailable || calls > 2 {
+			t.Errorf("got %v after %d calls with a cancelled context", err, calls)
+		}
+	})
+}
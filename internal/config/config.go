@@ -0,0 +1,338 @@
+// Package config holds the settings shared by the aggregation server and the
+// parties. Values come from defaults, a YAML or JSON file, HOSPITAL_* environment
+// variables and command-line flags, in increasing order of priority.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"hospital/internal/dp"
+	"hospital/internal/pki"
+	"hospital/internal/sharing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the configuration of a server or party process.
+type Config struct {
+	ListenAddr string `yaml:"listen_addr"` // address the server listens on
+	ServerAddr string `yaml:"server_addr"` // address parties dial to reach the server
+	Identity   string `yaml:"identity"`    // participant this process acts as
+	Modulus    string `yaml:"modulus"`     // prime of the field shares live in, like 2^127-1
+	Scale      int64  `yaml:"scale"`       // fixed-point scale real inputs are multiplied by before sharing
+	PolicyFile string `yaml:"policy_file"` // authorization policy of the server
+	StateFile  string `yaml:"state_file"`  // write-ahead log of the server's sessions, empty keeps them in memory only
+
+	// Mode is ModeCentral, where all shares go through the aggregation
+	// server, or ModeP2P, where every participant runs its own endpoint and
+	// the server only receives the final aggregate.
+	Mode  string            `yaml:"mode"`
+	Peers map[string]string `yaml:"peers"` // participant endpoints in peer-to-peer mode, name to address
+
+	TLS      TLSConfig      `yaml:"tls"`
+	Timeouts TimeoutsConfig `yaml:"timeouts"`
+	Retry    RetryConfig    `yaml:"retry"`
+	Privacy  PrivacyConfig  `yaml:"privacy"`
+
+	field *sharing.Field // parsed Modulus, set by Validate
+}
+
+// Modes of operation.
+const (
+	ModeCentral = "central"
+	ModeP2P     = "p2p"
+)
+
+// TLSConfig locates the certificates. Certificate and key default to
+// <cert_dir>/<name>-cert.pem and <cert_dir>/<name>-key.pem, where name is the
+// participant identity or "server".
+type TLSConfig struct {
+	CAFile   string `yaml:"ca_file"`
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	CertDir  string `yaml:"cert_dir"`
+}
+
+// TimeoutsConfig bounds how long the processes wait on each other.
+type TimeoutsConfig struct {
+	Request    time.Duration `yaml:"request"`     // per RPC sent by a party
+	Wait       time.Duration `yaml:"wait"`        // how long the server holds Get* calls for missing shares
+	CertReload time.Duration `yaml:"cert_reload"` // how often the server checks its certificates for changes
+	Shutdown   time.Duration `yaml:"shutdown"`    // how long a stopping server lets calls in flight finish
+	Keepalive  time.Duration `yaml:"keepalive"`   // how often parties ping idle connections, at least MinKeepalive
+}
+
+// MinKeepalive is the shortest keepalive interval servers accept. Clients
+// pinging more often are disconnected.
+const MinKeepalive = 10 * time.Second
+
+// RetryConfig controls how parties retry RPCs that failed with Unavailable or
+// DeadlineExceeded. Retries go on for up to Timeouts.Wait after the first
+// failure. The backoff doubles after every attempt up to MaxBackoff, and each
+// wait is drawn at random below it.
+type RetryConfig struct {
+	Attempts       int           `yaml:"attempts"` // total attempts per RPC, 0 for no limit besides time, 1 disables retries
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+}
+
+// PrivacyConfig sets up differential privacy. Parties add the noise described
+// by the embedded parameters to the sessions they create on Dataset. The
+// server refuses sessions that would spend more than MaxEpsilon and MaxDelta,
+// that would take a participant's spending on the dataset across sessions
+// beyond TotalEpsilon and TotalDelta, and with Required also those without
+// noise. Without Required, sessions naming no dataset and adding no noise are
+// outside the ledger, so it only binds every query when Required is set.
+type PrivacyConfig struct {
+	dp.Params    `yaml:",inline"`
+	Dataset      string  `yaml:"dataset"`     // dataset the party's sessions query
+	MaxEpsilon   float64 `yaml:"max_epsilon"` // budget of a single session
+	MaxDelta     float64 `yaml:"max_delta"`
+	TotalEpsilon float64 `yaml:"total_epsilon"` // budget of a participant per dataset
+	TotalDelta   float64 `yaml:"total_delta"`
+	LedgerFile   string  `yaml:"ledger_file"` // where the server keeps the spending per participant and dataset
+	Required     bool    `yaml:"required"`
+}
+
+// Default returns the configuration used when nothing else is given: a server
+// on localhost:50051 with the certificates in cert/.
+func Default() *Config {
+	return &Config{
+		ListenAddr: ":50051",
+		ServerAddr: "localhost:50051",
+		Modulus:    sharing.DefaultModulus,
+		Scale:      sharing.DefaultScale,
+		PolicyFile: "config/policy.json",
+		Mode:       ModeCentral,
+		TLS: TLSConfig{
+			CAFile:  "cert/ca-cert.pem",
+			CertDir: "cert",
+		},
+		Timeouts: TimeoutsConfig{
+			Request:    15 * time.Second,
+			Wait:       30 * time.Second,
+			CertReload: 30 * time.Second,
+			Shutdown:   10 * time.Second,
+			Keepalive:  30 * time.Second,
+		},
+		Retry: RetryConfig{
+			Attempts:       0,
+			InitialBackoff: 100 * time.Millisecond,
+			MaxBackoff:     5 * time.Second,
+		},
+		Privacy: PrivacyConfig{
+			Params: dp.Params{
+				Delta:       1e-6,
+				Sensitivity: 1,
+			},
+			MaxEpsilon:   1,
+			MaxDelta:     1e-5,
+			TotalEpsilon: 10,
+			TotalDelta:   1e-4,
+			LedgerFile:   "ledger.wal",
+		},
+	}
+}
+
+// KeyPair returns the certificate and key files to use for name.
+func (t TLSConfig) KeyPair(name string) (string, string) {
+	certFile, keyFile := pki.Files(t.CertDir, name)
+	if t.CertFile != "" {
+		certFile = t.CertFile
+	}
+	if t.KeyFile != "" {
+		keyFile = t.KeyFile
+	}
+	return certFile, keyFile
+}
+
+// Load builds the configuration. The file is taken from -config or
+// HOSPITAL_CONFIG. Callers may register their own flags on fs before calling
+// Load; fs is parsed with args.
+func Load(fs *flag.FlagSet, args []string) (*Config, error) {
+	cfg := Default()
+
+	path := os.Getenv("HOSPITAL_CONFIG")
+	if p, ok := configFlag(args); ok {
+		path = p
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	fs.String("config", path, "YAML or JSON configuration file (env HOSPITAL_CONFIG)")
+	cfg.bindFlags(fs)
+
+	// Environment variables override the file, flags override both
+	var envErr error
+	fs.VisitAll(func(f *flag.Flag) {
+		if f.Name == "config" {
+			return
+		}
+		if value, ok := os.LookupEnv(envName(f.Name)); ok && envErr == nil {
+			if err := fs.Set(f.Name, value); err != nil {
+				envErr = fmt.Errorf("invalid %s: %w", envName(f.Name), err)
+			}
+		}
+	})
+	if envErr != nil {
+		return nil, envErr
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	return cfg, cfg.Validate()
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	// JSON is valid YAML, so one decoder handles both
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Config) bindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.ListenAddr, "listen-addr", c.ListenAddr, "address the server listens on")
+	fs.StringVar(&c.ServerAddr, "server-addr", c.ServerAddr, "address of the aggregation server")
+	fs.StringVar(&c.Identity, "identity", c.Identity, "participant this process acts as")
+	fs.StringVar(&c.Modulus, "modulus", c.Modulus, "prime modulus of the field shares live in, in decimal, 0x hex or like 2^127-1")
+	fs.Int64Var(&c.Scale, "scale", c.Scale, "fixed-point scale, inputs are rounded to multiples of 1/scale")
+	fs.StringVar(&c.PolicyFile, "policy-file", c.PolicyFile, "authorization policy file")
+	fs.StringVar(&c.StateFile, "state-file", c.StateFile, "file the server logs its sessions to so they survive a restart, empty keeps them in memory")
+	fs.StringVar(&c.Mode, "mode", c.Mode, "central, or p2p to exchange shares directly between participants")
+	fs.Func("peers", "participant endpoints for p2p mode, like Alice=host:port,Bob=host:port", c.setPeers)
+	fs.StringVar(&c.TLS.CAFile, "ca-file", c.TLS.CAFile, "CA certificate")
+	fs.StringVar(&c.TLS.CertFile, "cert-file", c.TLS.CertFile, "own certificate, defaults to <cert-dir>/<name>-cert.pem")
+	fs.StringVar(&c.TLS.KeyFile, "key-file", c.TLS.KeyFile, "own private key, defaults to <cert-dir>/<name>-key.pem")
+	fs.StringVar(&c.TLS.CertDir, "cert-dir", c.TLS.CertDir, "directory holding the certificates")
+	fs.DurationVar(&c.Timeouts.Request, "request-timeout", c.Timeouts.Request, "timeout of a single RPC")
+	fs.DurationVar(&c.Timeouts.Wait, "wait-timeout", c.Timeouts.Wait, "how long the server waits for missing shares")
+	fs.DurationVar(&c.Timeouts.CertReload, "cert-reload-interval", c.Timeouts.CertReload, "how often the server checks its certificates for changes")
+	fs.DurationVar(&c.Timeouts.Shutdown, "shutdown-timeout", c.Timeouts.Shutdown, "how long a stopping server lets calls in flight finish")
+	fs.DurationVar(&c.Timeouts.Keepalive, "keepalive", c.Timeouts.Keepalive, "how often parties ping their connections to detect dead ones")
+	fs.IntVar(&c.Retry.Attempts, "retry-attempts", c.Retry.Attempts, "attempts per RPC, 0 retries for up to -wait-timeout, 1 disables retries")
+	fs.DurationVar(&c.Retry.InitialBackoff, "retry-backoff", c.Retry.InitialBackoff, "backoff before the first retry")
+	fs.DurationVar(&c.Retry.MaxBackoff, "retry-max-backoff", c.Retry.MaxBackoff, "upper bound of the backoff between retries")
+	fs.Func("dp", "differential privacy noise parties add to the output: laplace or gaussian, empty for none", func(s string) error {
+		c.Privacy.Mechanism = dp.Mechanism(s)
+		return nil
+	})
+	fs.Float64Var(&c.Privacy.Epsilon, "epsilon", c.Privacy.Epsilon, "privacy parameter epsilon of the noise")
+	fs.Float64Var(&c.Privacy.Delta, "delta", c.Privacy.Delta, "privacy parameter delta of gaussian noise")
+	fs.Float64Var(&c.Privacy.Sensitivity, "sensitivity", c.Privacy.Sensitivity, "most one individual can change the output")
+	fs.Float64Var(&c.Privacy.MaxEpsilon, "max-epsilon", c.Privacy.MaxEpsilon, "epsilon budget of a single session on the server")
+	fs.Float64Var(&c.Privacy.MaxDelta, "max-delta", c.Privacy.MaxDelta, "delta budget of a single session on the server")
+	fs.Float64Var(&c.Privacy.TotalEpsilon, "total-epsilon", c.Privacy.TotalEpsilon, "epsilon budget of every participant per dataset across sessions on the server")
+	fs.Float64Var(&c.Privacy.TotalDelta, "total-delta", c.Privacy.TotalDelta, "delta budget of every participant per dataset across sessions on the server")
+	fs.StringVar(&c.Privacy.LedgerFile, "ledger-file", c.Privacy.LedgerFile, "file the server keeps the privacy budget ledger in, apart from its sessions")
+	fs.StringVar(&c.Privacy.Dataset, "dataset", c.Privacy.Dataset, "dataset the sessions query, the server charges their privacy budget to it")
+	fs.BoolVar(&c.Privacy.Required, "require-dp", c.Privacy.Required, "make the server refuse sessions without differential privacy noise")
+}
+
+func (c *Config) setPeers(list string) error {
+	peers := make(map[string]string)
+	for _, entry := range strings.Split(list, ",") {
+		name, addr, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || name == "" || addr == "" {
+			return fmt.Errorf("invalid peer %q, expected name=host:port", entry)
+		}
+		peers[name] = addr
+	}
+	c.Peers = peers
+	return nil
+}
+
+// PeerAddr returns the endpoint of participant name in peer-to-peer mode.
+func (c *Config) PeerAddr(name string) (string, error) {
+	addr, ok := c.Peers[name]
+	if !ok {
+		return "", fmt.Errorf("no endpoint configured for peer %s", name)
+	}
+	return addr, nil
+}
+
+// Field returns the field shares live in. The configuration must have been
+// validated.
+func (c *Config) Field() *sharing.Field {
+	return c.field
+}
+
+// Encoding returns the fixed-point encoding of real inputs. The configuration
+// must have been validated.
+func (c *Config) Encoding() sharing.Encoding {
+	return sharing.Encoding{Scale: c.Scale, Field: c.field}
+}
+
+// envName maps a flag name to its environment variable, e.g. listen-addr to
+// HOSPITAL_LISTEN_ADDR.
+func envName(flagName string) string {
+	return "HOSPITAL_" + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// configFlag finds -config in args before the flags are parsed, since the
+// file has to be loaded first.
+func configFlag(args []string) (string, bool) {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "config" {
+			continue
+		}
+		if hasValue {
+			return value, true
+		}
+		if i+1 < len(args) {
+			return args[i+1], true
+		}
+	}
+	return "", false
+}
+
+// Validate checks that the configuration is usable.
+func (c *Config) Validate() error {
+	var errs []error
+	if field, err := sharing.ParseField(c.Modulus); err != nil {
+		errs = append(errs, err)
+	} else if _, err := sharing.NewEncoding(c.Scale, field); err != nil {
+		errs = append(errs, err)
+	} else {
+		c.field = field
+	}
+	if c.Timeouts.Request <= 0 || c.Timeouts.Wait <= 0 || c.Timeouts.CertReload <= 0 || c.Timeouts.Shutdown <= 0 {
+		errs = append(errs, errors.New("timeouts must be positive"))
+	}
+	if c.Timeouts.Keepalive < MinKeepalive {
+		errs = append(errs, fmt.Errorf("keepalive must be at least %v", MinKeepalive))
+	}
+	if c.Retry.Attempts < 0 || c.Retry.InitialBackoff <= 0 || c.Retry.MaxBackoff < c.Retry.InitialBackoff {
+		errs = append(errs, errors.New("retries need 0 or more attempts and 0 < retry-backoff <= retry-max-backoff"))
+	}
+	if c.Mode != ModeCentral && c.Mode != ModeP2P {
+		errs = append(errs, fmt.Errorf("unknown mode %q, expected %s or %s", c.Mode, ModeCentral, ModeP2P))
+	}
+	if err := c.Privacy.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Privacy.MaxEpsilon < 0 || c.Privacy.MaxDelta < 0 || c.Privacy.TotalEpsilon < 0 || c.Privacy.TotalDelta < 0 {
+		errs = append(errs, errors.New("privacy budget must not be negative"))
+	}
+	if c.TLS.CAFile == "" {
+		errs = append(errs, errors.New("no CA file configured"))
+	}
+	return errors.Join(errs...)
+}
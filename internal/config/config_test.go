@@ -0,0 +1,77 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"hospital/internal/dp"
+)
+
+func load(t *testing.T, args ...string) (*Config, error) {
+	t.Helper()
+	return Load(flag.NewFlagSet("test", flag.ContinueOnError), args)
+}
+
+func TestLoadExample(t *testing.T) {
+	cfg, err := load(t, "-config", filepath.Join("..", "..", "config", "hospital.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	def := Default()
+	if cfg.ListenAddr != def.ListenAddr || cfg.Timeouts != def.Timeouts || cfg.Retry != def.Retry || cfg.Privacy != def.Privacy {
+		t.Errorf("example configuration differs from the defaults:\n%+v\n%+v", cfg, def)
+	}
+	if cfg.Field() == nil {
+		t.Error("validated configuration has no field")
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	data := "listen_addr: \":1\"\nserver_addr: file:1\nidentity: FromFile\ntimeouts:\n  wait: 5s\n"
+	if err := os.WriteFile(file, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOSPITAL_CONFIG", file)
+	t.Setenv("HOSPITAL_SERVER_ADDR", "env:1")
+	t.Setenv("HOSPITAL_IDENTITY", "FromEnv")
+
+	cfg, err := load(t, "-identity", "FromFlag", "-dp", "laplace", "-epsilon", "0.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ListenAddr != ":1" || cfg.Timeouts.Wait != 5*time.Second {
+		t.Errorf("file values not loaded: %q, %v", cfg.ListenAddr, cfg.Timeouts.Wait)
+	}
+	if cfg.ServerAddr != "env:1" {
+		t.Errorf("server address %q, want the environment's", cfg.ServerAddr)
+	}
+	if cfg.Identity != "FromFlag" {
+		t.Errorf("identity %q, want the flag's", cfg.Identity)
+	}
+	if cfg.Privacy.Mechanism != dp.Laplace || cfg.Privacy.Epsilon != 0.5 {
+		t.Errorf("privacy %+v, want laplace with epsilon 0.5", cfg.Privacy.Params)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	for _, args := range [][]string{
+		{"-modulus", "2^127-2"},
+		{"-scale", "0"},
+		{"-mode", "mesh"},
+		{"-wait-timeout", "0s"},
+		{"-keepalive", "1s"},
+		{"-retry-attempts", "-1"},
+		{"-retry-backoff", "1s", "-retry-max-backoff", "10ms"},
+		{"-dp", "gaussian", "-epsilon", "2"},
+		{"-total-epsilon", "-1"},
+		{"-peers", "Alice"},
+	} {
+		if _, err := load(t, args...); err == nil {
+			t.Errorf("%v loaded", args)
+		}
+	}
+}
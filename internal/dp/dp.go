@@ -0,0 +1,140 @@
+// Package dp calibrates the differential privacy noise parties add to an
+// aggregate. Every party draws its own share of the noise, and the shares of
+// any parties but one add up to the noise of the mechanism, so the
+// reconstructed aggregate stays differentially private even towards a party
+// that subtracts its own share.
+package dp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Mechanism is the kind of noise added.
+type Mechanism string
+
+// The supported mechanisms.
+const (
+	None     Mechanism = ""
+	Laplace  Mechanism = "laplace"  // discrete Laplace noise, (ε, 0)-DP
+	Gaussian Mechanism = "gaussian" // discrete Gaussian noise, (ε, δ)-DP
+)
+
+// Params describes the noise of a release. Sensitivity is the most a single
+// individual can change the aggregate: the L1 norm of the change for Laplace
+// noise, the L2 norm for Gaussian noise.
+type Params struct {
+	Mechanism   Mechanism `yaml:"mechanism"`
+	Epsilon     float64   `yaml:"epsilon"`
+	Delta       float64   `yaml:"delta"` // only used by Gaussian noise
+	Sensitivity float64   `yaml:"sensitivity"`
+}
+
+// Enabled reports whether noise is added at all.
+func (p Params) Enabled() bool {
+	return p.Mechanism != None
+}
+
+// Validate checks that the parameters describe a usable mechanism.
+func (p Params) Validate() error {
+	switch p.Mechanism {
+	case None:
+		return nil
+	case Laplace:
+		if !(p.Epsilon > 0) || math.IsInf(p.Epsilon, 0) {
+			return fmt.Errorf("laplace noise needs epsilon > 0, got %v", p.Epsilon)
+		}
+	case Gaussian:
+		// The classic calibration of the Gaussian mechanism holds for ε < 1
+		if !(p.Epsilon > 0 && p.Epsilon < 1) {
+			return fmt.Errorf("gaussian noise needs 0 < epsilon < 1, got %v", p.Epsilon)
+		}
+		if !(p.Delta > 0 && p.Delta < 1) {
+			return fmt.Errorf("gaussian noise needs 0 < delta < 1, got %v", p.Delta)
+		}
+	default:
+		return fmt.Errorf("unknown mechanism %q, expected %s or %s", p.Mechanism, Laplace, Gaussian)
+	}
+	if !(p.Sensitivity > 0) || math.IsInf(p.Sensitivity, 0) {
+		return fmt.Errorf("sensitivity must be positive, got %v", p.Sensitivity)
+	}
+	return nil
+}
+
+// Cost returns the privacy budget a release with these parameters spends.
+func (p Params) Cost() (epsilon, delta float64) {
+	switch p.Mechanism {
+	case Laplace:
+		return p.Epsilon, 0
+	case Gaussian:
+		return p.Epsilon, p.Delta
+	}
+	return 0, 0
+}
+
+// Scale returns the scale of the total noise in units of the aggregate: the
+// scale b of Laplace noise, or the standard deviation σ of Gaussian noise.
+func (p Params) Scale() float64 {
+	switch p.Mechanism {
+	case Laplace:
+		return p.Sensitivity / p.Epsilon
+	case Gaussian:
+		return p.Sensitivity * math.Sqrt(2*math.Log(1.25/p.Delta)) / p.Epsilon
+	}
+	return 0
+}
+
+// errTooLarge is returned when the noise does not fit an int64.
+var errTooLarge = errors.New("noise too large, lower the sensitivity or the fixed-point scale")
+
+// NoiseShare draws one party's share of the noise for length elements, for
+// parties parties in total. unit is the integer the value 1 of the aggregate
+// is encoded as, e.g. the fixed-point scale, so the noise is drawn on the
+// same integer grid as the encoded aggregate.
+//
+// Every party knows its own share and can subtract it from the aggregate, so
+// the shares are calibrated for parties-1 honest parties: for Laplace noise
+// every party adds the difference of two Pólya variables, and any parties-1
+// of them sum up to the difference of two geometric variables, exactly
+// discrete Laplace noise. For Gaussian noise every party adds discrete
+// Gaussian noise with variance σ²/(parties-1), and any parties-1 of them sum
+// up to noise close to a discrete Gaussian with variance σ². The aggregate
+// thus carries somewhat more noise than the mechanism needs. The guarantee
+// holds against any single party as long as the others add their share;
+// parties colluding with each other learn an aggregate with less noise.
+func (p Params) NoiseShare(length, parties int, unit int64) ([]int64, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if parties < 1 || unit < 1 {
+		return nil, fmt.Errorf("invalid noise share for %d parties and unit %d", parties, unit)
+	}
+	noise := make([]int64, length)
+	if !p.Enabled() {
+		return noise, nil
+	}
+
+	s, err := newSampler()
+	if err != nil {
+		return nil, err
+	}
+	scale := p.Scale() * float64(unit)
+	honest := float64(max(parties-1, 1))
+	for i := range noise {
+		var x float64
+		switch p.Mechanism {
+		case Laplace:
+			// P(k) ∝ q^|k| with q = exp(-1/scale)
+			q := math.Exp(-1 / scale)
+			x = s.polya(1/honest, q) - s.polya(1/honest, q)
+		case Gaussian:
+			x = s.discreteGaussian(scale / math.Sqrt(honest))
+		}
+		if math.Abs(x) > 1<<62 {
+			return nil, errTooLarge
+		}
+		noise[i] = int64(x)
+	}
+	return noise, nil
+}
@@ -0,0 +1,127 @@
+package dp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		params Params
+		ok     bool
+	}{
+		{Params{}, true},
+		{Params{Mechanism: Laplace, Epsilon: 0.5, Sensitivity: 1}, true},
+		{Params{Mechanism: Laplace, Epsilon: 0, Sensitivity: 1}, false},
+		{Params{Mechanism: Laplace, Epsilon: math.Inf(1), Sensitivity: 1}, false},
+		{Params{Mechanism: Laplace, Epsilon: 1, Sensitivity: 0}, false},
+		{Params{Mechanism: Gaussian, Epsilon: 0.5, Delta: 1e-6, Sensitivity: 1}, true},
+		{Params{Mechanism: Gaussian, Epsilon: 1, Delta: 1e-6, Sensitivity: 1}, false},
+		{Params{Mechanism: Gaussian, Epsilon: 0.5, Delta: 0, Sensitivity: 1}, false},
+		{Params{Mechanism: "uniform", Epsilon: 0.5, Sensitivity: 1}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.params.Validate(); (err == nil) != tt.ok {
+			t.Errorf("%+v: Validate() = %v, want ok %v", tt.params, err, tt.ok)
+		}
+	}
+}
+
+func TestCost(t *testing.T) {
+	laplace := Params{Mechanism: Laplace, Epsilon: 0.5, Delta: 1e-6, Sensitivity: 1}
+	if eps, delta := laplace.Cost(); eps != 0.5 || delta != 0 {
+		t.Errorf("laplace costs %v, %v; want 0.5, 0", eps, delta)
+	}
+	gaussian := Params{Mechanism: Gaussian, Epsilon: 0.5, Delta: 1e-6, Sensitivity: 1}
+	if eps, delta := gaussian.Cost(); eps != 0.5 || delta != 1e-6 {
+		t.Errorf("gaussian costs %v, %v; want 0.5, 1e-6", eps, delta)
+	}
+	if eps, delta := (Params{}).Cost(); eps != 0 || delta != 0 {
+		t.Errorf("no noise costs %v, %v", eps, delta)
+	}
+}
+
+func TestNoiseShareInvalid(t *testing.T) {
+	p := Params{Mechanism: Laplace, Epsilon: 1, Sensitivity: 1}
+	for _, tt := range []struct{ parties, unit int }{{0, 1}, {3, 0}} {
+		if _, err := p.NoiseShare(1, tt.parties, int64(tt.unit)); err == nil {
+			t.Errorf("NoiseShare for %d parties and unit %d succeeded", tt.parties, tt.unit)
+		}
+	}
+	noise, err := Params{}.NoiseShare(3, 3, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, x := range noise {
+		if x != 0 {
+			t.Fatalf("no noise drew %v", noise)
+		}
+	}
+}
+
+// partialSums draws a noise share for every party and returns, per element,
+// the sum of all shares but the last party's: the noise a party that
+// subtracts its own share still sees.
+func partialSums(t *testing.T, p Params, parties, length int, unit int64) []float64 {
+	t.Helper()
+	sums := make([]float64, length)
+	for range parties - 1 {
+		shares, err := p.NoiseShare(length, parties, unit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, x := range shares {
+			sums[i] += float64(x)
+		}
+	}
+	return sums
+}
+
+func TestNoiseShareCalibration(t *testing.T) {
+	const n = 50000
+	laplace := Params{Mechanism: Laplace, Epsilon: 1, Sensitivity: 1}
+	q := math.Exp(-1 / laplace.Scale())
+	gaussian := Params{Mechanism: Gaussian, Epsilon: 0.5, Delta: 1e-5, Sensitivity: 1}
+
+	tests := []struct {
+		name     string
+		params   Params
+		parties  int
+		variance float64 // of the noise of any parties-1 shares
+	}{
+		{"laplace", laplace, 3, 2 * q / ((1 - q) * (1 - q))},
+		{"laplace two parties", laplace, 2, 2 * q / ((1 - q) * (1 - q))},
+		{"gaussian", gaussian, 3, gaussian.Scale() * gaussian.Scale()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mean, variance := moments(partialSums(t, tt.params, tt.parties, n, 1))
+			if math.Abs(mean) > 5*math.Sqrt(tt.variance/n) {
+				t.Errorf("mean %v, want 0", mean)
+			}
+			if math.Abs(variance/tt.variance-1) > 0.08 {
+				t.Errorf("variance %v, want %v", variance, tt.variance)
+			}
+		})
+	}
+}
+
+func TestNoiseShareUnit(t *testing.T) {
+	// With a unit of 1000 the noise is on the grid of the encoded values
+	p := Params{Mechanism: Laplace, Epsilon: 1, Sensitivity: 1}
+	_, variance := moments(partialSums(t, p, 3, 50000, 1000))
+	if want := 2e6; math.Abs(variance/want-1) > 0.05 {
+		t.Errorf("variance %v, want about %v", variance, want)
+	}
+}
+
+func moments(xs []float64) (mean, variance float64) {
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= float64(len(xs))
+	for _, x := range xs {
+		variance += (x - mean) * (x - mean)
+	}
+	return mean, variance / float64(len(xs)-1)
+}
@@ -0,0 +1,121 @@
+package dp
+
+import (
+	crand "crypto/rand"
+	"math"
+	"math/rand/v2"
+)
+
+// sampler draws the random variables the noise is built from. Its generator
+// is seeded from crypto/rand, so the noise cannot be predicted.
+type sampler struct {
+	rng *rand.Rand
+}
+
+func newSampler() (*sampler, error) {
+	var seed [32]byte
+	if _, err := crand.Read(seed[:]); err != nil {
+		return nil, err
+	}
+	return &sampler{rng: rand.New(rand.NewChaCha8(seed))}, nil
+}
+
+// uniform returns a value in (0, 1].
+func (s *sampler) uniform() float64 {
+	return 1 - s.rng.Float64()
+}
+
+// geometric returns the number of failures before the first success, where
+// every trial fails with probability q.
+func (s *sampler) geometric(q float64) float64 {
+	return math.Floor(math.Log(s.uniform()) / math.Log(q))
+}
+
+// discreteLaplace returns x with probability proportional to exp(-|x|/t).
+func (s *sampler) discreteLaplace(t float64) float64 {
+	q := math.Exp(-1 / t)
+	return s.geometric(q) - s.geometric(q)
+}
+
+// discreteGaussian returns x with probability proportional to
+// exp(-x²/(2σ²)), by rejection from discrete Laplace noise as described by
+// Canonne, Kamath and Steinke, "The Discrete Gaussian for Differential
+// Privacy", 2020.
+func (s *sampler) discreteGaussian(sigma float64) float64 {
+	t := math.Floor(sigma) + 1
+	for {
+		y := s.discreteLaplace(t)
+		d := math.Abs(y) - sigma*sigma/t
+		if s.rng.Float64() < math.Exp(-d*d/(2*sigma*sigma)) {
+			return y
+		}
+	}
+}
+
+// polya returns a negative binomial variable with real shape r: the number of
+// failures, each with probability q, before r successes. The sum of n
+// variables with shape 1/n is geometric. It is drawn as a Poisson variable
+// whose mean is gamma distributed.
+func (s *sampler) polya(r, q float64) float64 {
+	return s.poisson(s.gamma(r) * q / (1 - q))
+}
+
+// gamma returns a gamma variable with shape k and scale 1, with the method of
+// Marsaglia and Tsang, "A Simple Method for Generating Gamma Variables", 2000.
+func (s *sampler) gamma(k float64) float64 {
+	if k < 1 {
+		return s.gamma(k+1) * math.Pow(s.uniform(), 1/k)
+	}
+	d := k - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := s.rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := s.uniform()
+		if u < 1-0.0331*x*x*x*x || math.Log(u) < 0.5*x*x+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
+
+// poisson returns a Poisson variable with mean lambda. Small means multiply
+// uniforms; large ones use the transformed rejection of Hörmann, "The
+// transformed rejection method for generating Poisson random variables",
+// 1993.
+func (s *sampler) poisson(lambda float64) float64 {
+	if lambda < 10 {
+		limit, product := math.Exp(-lambda), s.uniform()
+		var k float64
+		for product > limit {
+			product *= s.uniform()
+			k++
+		}
+		return k
+	}
+
+	logLambda := math.Log(lambda)
+	b := 0.931 + 2.53*math.Sqrt(lambda)
+	a := -0.059 + 0.02483*b
+	invAlpha := 1.1239 + 1.1328/(b-3.4)
+	vr := 0.9277 - 3.6224/(b-2)
+	for {
+		u := s.rng.Float64() - 0.5
+		v := s.rng.Float64()
+		us := 0.5 - math.Abs(u)
+		k := math.Floor((2*a/us+b)*u + lambda + 0.43)
+		if us >= 0.07 && v <= vr {
+			return k
+		}
+		if k < 0 || (us < 0.013 && v > us) {
+			continue
+		}
+		lgamma, _ := math.Lgamma(k + 1)
+		if math.Log(v)+math.Log(invAlpha)-math.Log(a/(us*us)+b) <= -lambda+k*logLambda-lgamma {
+			return k
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package dp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSamplerMoments(t *testing.T) {
+	s, err := newSampler()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 100000
+	tests := []struct {
+		name           string
+		draw           func() float64
+		mean, variance float64
+	}{
+		{"geometric", func() float64 { return s.geometric(0.6) }, 0.6 / 0.4, 0.6 / (0.4 * 0.4)},
+		{"poisson small", func() float64 { return s.poisson(3) }, 3, 3},
+		{"poisson large", func() float64 { return s.poisson(250) }, 250, 250},
+		{"gamma below 1", func() float64 { return s.gamma(0.3) }, 0.3, 0.3},
+		{"gamma", func() float64 { return s.gamma(4.5) }, 4.5, 4.5},
+		{"polya", func() float64 { return s.polya(0.5, 0.9) }, 0.5 * 0.9 / 0.1, 0.5 * 0.9 / (0.1 * 0.1)},
+		{"discrete gaussian", func() float64 { return s.discreteGaussian(3) }, 0, 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			xs := make([]float64, n)
+			for i := range xs {
+				xs[i] = tt.draw()
+			}
+			mean, variance := moments(xs)
+			if math.Abs(mean-tt.mean) > 5*math.Sqrt(tt.variance/n) {
+				t.Errorf("mean %v, want %v", mean, tt.mean)
+			}
+			if math.Abs(variance/tt.variance-1) > 0.08 {
+				t.Errorf("variance %v, want %v", variance, tt.variance)
+			}
+		})
+	}
+}
+
+func TestSamplerIntegers(t *testing.T) {
+	s, err := newSampler()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 1000 {
+		for _, x := range []float64{s.geometric(0.5), s.poisson(20), s.polya(0.3, 0.8), s.discreteGaussian(2.5)} {
+			if x != math.Trunc(x) || math.IsInf(x, 0) {
+				t.Fatalf("drew %v, want an integer", x)
+			}
+		}
+	}
+}
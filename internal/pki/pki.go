@@ -0,0 +1,213 @@
+// Package pki issues the CA, server and participant certificates used for
+// mutual TLS between the hospitals and the aggregation server.
+package pki
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CA is a certificate authority able to sign server and client certificates.
+type CA struct {
+	Cert *x509.Certificate
+	Key  crypto.Signer
+}
+
+// Files returns the certificate and key file names for name inside dir,
+// e.g. cert/alice-cert.pem and cert/alice-key.pem. Names differing only in
+// case share their files, see CheckNames.
+func Files(dir, name string) (string, string) {
+	base := strings.ToLower(name)
+	return filepath.Join(dir, base+"-cert.pem"), filepath.Join(dir, base+"-key.pem")
+}
+
+// CheckNames makes sure the participants get files of their own: no two of
+// them differ only in case, and none takes the files of the CA or the
+// server. Otherwise one participant's certificate would overwrite another's,
+// which would then present the wrong identity.
+func CheckNames(participants []string) error {
+	taken := map[string]string{"ca": "the CA", "server": "the server"}
+	for _, name := range participants {
+		base := strings.ToLower(name)
+		if other, ok := taken[base]; ok {
+			return fmt.Errorf("%s would share its certificate files with %s", name, other)
+		}
+		taken[base] = name
+	}
+	return nil
+}
+
+// NewCA creates a self-signed CA valid for the given duration.
+func NewCA(subject pkix.Name, validity time.Duration) (*CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template, err := newTemplate(subject, validity)
+	if err != nil {
+		return nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &CA{Cert: cert, Key: key}, nil
+}
+
+// LoadCA reads a CA certificate and its private key from PEM files.
+func LoadCA(certFile, keyFile string) (*CA, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("%s: no certificate found", certFile)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", certFile, err)
+	}
+	if !cert.IsCA {
+		return nil, fmt.Errorf("%s: not a CA certificate", certFile)
+	}
+
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", keyFile, err)
+	}
+	return &CA{Cert: cert, Key: key}, nil
+}
+
+// parsePrivateKey accepts PKCS#8 keys as well as the PKCS#1 and SEC 1 keys
+// openssl used to write.
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no private key found")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, errors.New("private key cannot sign")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("unsupported private key format")
+}
+
+// IssueServer issues a server certificate for the given host names and IP
+// addresses.
+func (ca *CA) IssueServer(sans []string, validity time.Duration) ([]byte, crypto.Signer, error) {
+	if len(sans) == 0 {
+		return nil, nil, errors.New("server certificate needs at least one SAN")
+	}
+	template, err := newTemplate(pkix.Name{Organization: []string{"Hospital"}, CommonName: sans[0]}, validity)
+	if err != nil {
+		return nil, nil, err
+	}
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	addSANs(template, sans)
+	return ca.issue(template)
+}
+
+// IssueParticipant issues a certificate for a participant. The name is used
+// as common name and DNS SAN; the server only accepts messages sent as that
+// name. The certificate also serves the participant's own endpoint in
+// peer-to-peer mode, where peers verify it against the participant's name.
+func (ca *CA) IssueParticipant(name string, validity time.Duration) ([]byte, crypto.Signer, error) {
+	template, err := newTemplate(pkix.Name{Organization: []string{"Hospital"}, OrganizationalUnit: []string{name}, CommonName: name}, validity)
+	if err != nil {
+		return nil, nil, err
+	}
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth}
+	template.DNSNames = []string{name}
+	return ca.issue(template)
+}
+
+func (ca *CA) issue(template *x509.Certificate) ([]byte, crypto.Signer, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.BasicConstraintsValid = true
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, key.Public(), ca.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return der, key, nil
+}
+
+func newTemplate(subject pkix.Name, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      subject,
+		NotBefore:    now.Add(-5 * time.Minute), // tolerate small clock skew
+		NotAfter:     now.Add(validity),
+	}, nil
+}
+
+func addSANs(template *x509.Certificate, sans []string) {
+	for _, san := range sans {
+		if ip := net.ParseIP(san); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, san)
+		}
+	}
+}
+
+// WriteCA writes the CA certificate and key as PEM files.
+func (ca *CA) WriteCA(certFile, keyFile string) error {
+	return WriteKeyPair(certFile, keyFile, ca.Cert.Raw, ca.Key)
+}
+
+// WriteKeyPair writes a DER certificate and its private key as PEM files. The
+// key file is only readable by its owner.
+func WriteKeyPair(certFile, keyFile string, der []byte, key crypto.Signer) error {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0600)
+}
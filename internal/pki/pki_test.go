@@ -0,0 +1,127 @@
+package pki
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// verify checks that der chains up to ca for the given usage and host name.
+func verify(t *testing.T, ca *CA, der []byte, usage x509.ExtKeyUsage, name string) {
+	t.Helper()
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, DNSName: name, KeyUsages: []x509.ExtKeyUsage{usage}}); err != nil {
+		t.Errorf("%s for %v: %v", name, usage, err)
+	}
+}
+
+func TestIssue(t *testing.T) {
+	ca, err := NewCA(pkix.Name{CommonName: "Test CA"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, _, err := ca.IssueServer([]string{"localhost", "127.0.0.1"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify(t, ca, der, x509.ExtKeyUsageServerAuth, "localhost")
+	verify(t, ca, der, x509.ExtKeyUsageServerAuth, "127.0.0.1")
+	if _, _, err := ca.IssueServer(nil, time.Hour); err == nil {
+		t.Error("issued a server certificate without SANs")
+	}
+
+	// A participant's certificate authenticates it as a client and serves its
+	// own endpoint in peer-to-peer mode
+	der, _, err = ca.IssueParticipant("Alice", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify(t, ca, der, x509.ExtKeyUsageClientAuth, "Alice")
+	verify(t, ca, der, x509.ExtKeyUsageServerAuth, "Alice")
+}
+
+func TestCheckNames(t *testing.T) {
+	if err := CheckNames([]string{"Alice", "Bob", "Charlie"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, names := range [][]string{{"Alice", "alice"}, {"Bob", "Server"}, {"CA"}} {
+		if err := CheckNames(names); err == nil {
+			t.Errorf("CheckNames(%q) succeeded", names)
+		}
+	}
+}
+
+func TestWriteLoadCA(t *testing.T) {
+	dir := t.TempDir()
+	ca, err := NewCA(pkix.Name{CommonName: "Test CA"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile := Files(dir, "CA")
+	if filepath.Base(certFile) != "ca-cert.pem" || filepath.Base(keyFile) != "ca-key.pem" {
+		t.Errorf("files %s and %s", certFile, keyFile)
+	}
+	if err := ca.WriteCA(certFile, keyFile); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(keyFile); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("key file %v, %v, want mode 0600", info, err)
+	}
+
+	loaded, err := LoadCA(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, key, err := loaded.IssueParticipant("Bob", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify(t, ca, der, x509.ExtKeyUsageClientAuth, "Bob")
+
+	// A leaf certificate cannot act as the CA
+	leafCert, leafKey := Files(dir, "bob")
+	if err := WriteKeyPair(leafCert, leafKey, der, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCA(leafCert, leafKey); err == nil {
+		t.Error("loaded a participant certificate as CA")
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sec1, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, block := range []*pem.Block{{Type: "PRIVATE KEY", Bytes: pkcs8}, {Type: "EC PRIVATE KEY", Bytes: sec1}} {
+		if _, err := parsePrivateKey(pem.EncodeToMemory(block)); err != nil {
+			t.Errorf("%s: %v", block.Type, err)
+		}
+	}
+	for _, data := range []string{"", "not pem", string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("junk")}))} {
+		if _, err := parsePrivateKey([]byte(data)); err == nil {
+			t.Errorf("parsed %q", data)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package pki
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Reloader serves a certificate, its key and a CA bundle from disk and picks
+// up new versions of the files without a restart. Only new handshakes see the
+// new files, established connections and their sessions keep running.
+type Reloader struct {
+	certFile, keyFile, caFile string
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	caPool   *x509.CertPool
+	modTimes []time.Time // of certFile, keyFile and caFile when last loaded
+}
+
+// NewReloader loads the files once. caFile holds the CAs client certificates
+// must be signed by.
+func NewReloader(certFile, keyFile, caFile string) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Watch checks the files every interval until ctx is done. If the new files
+// cannot be loaded, e.g. because only the certificate was replaced so far, the
+// previous ones stay in use and the next check tries again.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := r.reload()
+			if err != nil {
+				log.Printf("Keeping current certificates, reload failed: %v", err)
+			} else if reloaded {
+				log.Printf("Reloaded certificate %s", r.certFile)
+			}
+		}
+	}
+}
+
+// reload loads the files if any of them changed since the last load.
+func (r *Reloader) reload() (bool, error) {
+	modTimes, err := r.stat()
+	if err != nil {
+		return false, err
+	}
+	r.mu.RLock()
+	unchanged := r.modTimes != nil && equalTimes(r.modTimes, modTimes)
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, err
+	}
+	pemCA, err := os.ReadFile(r.caFile)
+	if err != nil {
+		return false, err
+	}
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(pemCA) {
+		return false, fmt.Errorf("no CA certificates found in %s", r.caFile)
+	}
+
+	r.mu.Lock()
+	r.cert, r.caPool, r.modTimes = &cert, caPool, modTimes
+	r.mu.Unlock()
+	return true, nil
+}
+
+func (r *Reloader) stat() ([]time.Time, error) {
+	var modTimes []time.Time
+	for _, file := range []string{r.certFile, r.keyFile, r.caFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes = append(modTimes, info.ModTime())
+	}
+	return modTimes, nil
+}
+
+func equalTimes(a, b []time.Time) bool {
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ServerConfig returns a TLS config that requires client certificates signed
+// by the current CA bundle and presents the current server certificate. Both
+// can change, so every handshake gets a config built from the current files.
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			return &tls.Config{
+				Certificates: []tls.Certificate{*r.cert},
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				ClientCAs:    r.caPool,
+				NextProtos:   []string{"h2"}, // gRPC only sets this on the outer config
+			}, nil
+		},
+	}
+}
@@ -0,0 +1,91 @@
+package pki
+
+import (
+	"bytes"
+	"crypto/x509/pkix"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeServer issues a server certificate into dir and returns its DER.
+func writeServer(t *testing.T, ca *CA, dir string) []byte {
+	t.Helper()
+	der, key, err := ca.IssueServer([]string{"localhost"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile := Files(dir, "server")
+	if err := WriteKeyPair(certFile, keyFile, der, key); err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+// served returns the DER of the certificate a handshake would present.
+func served(t *testing.T, r *Reloader) []byte {
+	t.Helper()
+	cfg, err := r.ServerConfig().GetConfigForClient(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg.Certificates[0].Certificate[0]
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	ca, err := NewCA(pkix.Name{CommonName: "Test CA"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caFile, caKeyFile := Files(dir, "ca")
+	if err := ca.WriteCA(caFile, caKeyFile); err != nil {
+		t.Fatal(err)
+	}
+	first := writeServer(t, ca, dir)
+	certFile, keyFile := Files(dir, "server")
+
+	r, err := NewReloader(certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(served(t, r), first) {
+		t.Fatal("reloader does not serve the certificate on disk")
+	}
+	if reloaded, err := r.reload(); err != nil || reloaded {
+		t.Fatalf("reload of unchanged files = %v, %v", reloaded, err)
+	}
+
+	// Renewed files are picked up by the next handshake
+	second := writeServer(t, ca, dir)
+	later := time.Now().Add(time.Minute)
+	for _, file := range []string{certFile, keyFile} {
+		if err := os.Chtimes(file, later, later); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if reloaded, err := r.reload(); err != nil || !reloaded {
+		t.Fatalf("reload of renewed files = %v, %v", reloaded, err)
+	}
+	if !bytes.Equal(served(t, r), second) {
+		t.Fatal("reloader still serves the old certificate")
+	}
+
+	// A broken file keeps the current certificate
+	if err := os.WriteFile(keyFile, []byte("garbage"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(keyFile, later.Add(time.Minute), later.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.reload(); err == nil {
+		t.Fatal("reload of a broken key succeeded")
+	}
+	if !bytes.Equal(served(t, r), second) {
+		t.Fatal("broken key replaced the current certificate")
+	}
+	if _, err := NewReloader(certFile, filepath.Join(dir, "missing.pem"), caFile); err == nil {
+		t.Fatal("NewReloader with a missing key succeeded")
+	}
+}
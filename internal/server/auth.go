@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"log"
+	"slices"
+
+	pb "hospital/api"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// callerIdentities returns the names the caller's verified client certificate
+// was issued for: its subject common name and DNS SANs.
+func callerIdentities(ctx context.Context) ([]string, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no peer information")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "no verified client certificate")
+	}
+
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	var names []string
+	if cert.Subject.CommonName != "" {
+		names = append(names, cert.Subject.CommonName)
+	}
+	for _, name := range cert.DNSNames {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// claimedIdentity returns the participant a request speaks for: the sender of
+// shares, or the participant whose sums are read.
+func claimedIdentity(req any) (string, bool) {
+	switch r := req.(type) {
+	case interface{ GetFrom() string }:
+		return r.GetFrom(), true
+	case interface{ GetParticipant() string }:
+		return r.GetParticipant(), true
+	}
+	return "", false
+}
+
+// boundIdentity returns the participant a request acts as, which the caller
+// must hold a certificate for whatever the policy allows. The Participant of
+// GetBudget only filters the ledger, so it is left to the policy.
+func boundIdentity(req any) (string, bool) {
+	if _, ok := req.(*pb.GetBudgetRequest); ok {
+		return "", false
+	}
+	return claimedIdentity(req)
+}
+
+// sessionParticipants returns who takes part in the session a request is
+// about: the proposed participants for CreateSession, the registered ones for
+// everything else. The results of a closed session remain readable by those
+// who published them.
+func (s *server) sessionParticipants(req any) []string {
+	switch r := req.(type) {
+	case *pb.CreateSessionRequest:
+		return r.Participants
+	case interface{ GetSession() string }:
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		if sess, ok := s.sessions[r.GetSession()]; ok {
+			return sess.participants
+		}
+		if _, ok := req.(*pb.GetAggregateRequest); ok {
+			var publishers []string
+			for from := range s.published[r.GetSession()] {
+				publishers = append(publishers, from)
+			}
+			return publishers
+		}
+	}
+	return nil
+}
+
+// authInterceptor rejects requests whose From or Participant field does not
+// match the identity in the caller's client certificate, so a hospital can
+// neither inject nor read shares as another hospital, whatever the policy
+// says. Every call is then checked against the server's policy.
+func (s *server) authInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	names, err := callerIdentities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if claimed, ok := boundIdentity(req); ok && !slices.Contains(names, claimed) {
+		log.Printf("Denied %s for %v acting as %q", info.FullMethod, names, claimed)
+		return nil, status.Errorf(codes.PermissionDenied, "certificate for %v cannot act as %q", names, claimed)
+	}
+	if err := s.policy.authorize(info.FullMethod, names, req, s.sessionParticipants); err != nil {
+		log.Printf("Denied %s for %v", info.FullMethod, names)
+		return nil, err
+	}
+	return handler(ctx, req)
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"log"
+	"math/big"
+
+	pb "hospital/api"
+	"hospital/internal/storage"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxMasks bounds the bit masks dealt per session. Every mask takes one
+// element per bit of the modulus, so there are fewer than triples.
+const maxMasks = 1 << 12
+
+// maskSize returns the elements of a participant's share of a bit mask: the
+// mask itself followed by one per bit of the modulus.
+func (s *server) maskSize() int {
+	return 1 + s.field.Bits()
+}
+
+// dealtMasks returns the number of bit masks handed out in the session.
+func (s *server) dealtMasks(sess *session) int {
+	return len(sess.masks[sess.participants[0]]) / s.maskSize()
+}
+
+// GetBitMasks returns req.Participant's shares of the bit masks numbered
+// req.Offset up to req.Offset+req.Count. Like GetTriples the server acts as
+// trusted dealer: it draws a random field element r and shares r and each of
+// its bits additively among the participants. Opening a shared value plus r
+// reveals nothing about the value to the participants, and the shared bits of
+// r let them compare the value bit by bit. The dealer stores every share and
+// so knows r; in central mode, where the openings pass through it, it learns
+// the compared value.
+func (s *server) GetBitMasks(ctx context.Context, req *pb.GetBitMasksRequest) (*pb.GetBitMasksResponse, error) {
+	if err := s.checkOwner(req.Participant); err != nil {
+		return nil, err
+	}
+	end := uint64(req.Offset) + uint64(req.Count)
+	if req.Count == 0 || end > maxMasks {
+		return nil, status.Errorf(codes.InvalidArgument, "masks [%d, %d) out of range [0, %d)", req.Offset, end, maxMasks)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, err := s.lookupSession(req.Session)
+	if err != nil {
+		return nil, err
+	}
+	if !sess.hasParticipant(req.Participant) {
+		return nil, status.Errorf(codes.InvalidArgument, "%q is not a participant of this session", req.Participant)
+	}
+	if err := sess.checkExact(req.Session); err != nil {
+		return nil, err
+	}
+	if sess.scheme != pb.Scheme_ADDITIVE {
+		return nil, status.Errorf(codes.FailedPrecondition, "session %q uses %s sharing, bit masks need additive sharing", req.Session, sess.scheme)
+	}
+
+	if missing := int(end) - s.dealtMasks(sess); missing > 0 {
+		values, err := s.dealMasks(missing, len(sess.participants))
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "could not generate bit masks: %v", err)
+		}
+		if err := s.record(storage.Record{Kind: storage.KindMasks, Session: req.Session, Values: values}); err != nil {
+			return nil, err
+		}
+		log.Printf("Session %s: dealt %d bit masks", req.Session, missing)
+	}
+
+	size := s.maskSize()
+	shares := sess.masks[req.Participant][size*int(req.Offset) : size*int(end)]
+	response := &pb.GetBitMasksResponse{}
+	for i := 0; i < len(shares); i += size {
+		response.Masks = append(response.Masks, &pb.BitMask{
+			Value: s.field.Bytes(shares[i]),
+			Bits:  s.field.Marshal(shares[i+1 : i+size]),
+		})
+	}
+	return response, nil
+}
+
+// dealMasks generates count bit masks shared among n participants. For every
+// mask in turn it returns each participant's share of the mask followed by its
+// shares of the bits, the layout of a KindMasks record.
+func (s *server) dealMasks(count, n int) ([]*big.Int, error) {
+	values := make([]*big.Int, 0, s.maskSize()*n*count)
+	for range count {
+		r, err := s.field.Random()
+		if err != nil {
+			return nil, err
+		}
+		mask := make([]*big.Int, s.maskSize())
+		mask[0] = r
+		for i := range s.field.Bits() {
+			mask[1+i] = big.NewInt(int64(r.Bit(i)))
+		}
+		shares, err := s.field.ShareVector(mask, n)
+		if err != nil {
+			return nil, err
+		}
+		for _, share := range shares {
+			values = append(values, share...)
+		}
+	}
+	return values, nil
+}
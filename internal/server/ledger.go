@@ -0,0 +1,147 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	pb "hospital/api"
+	"hospital/internal/dp"
+	"hospital/internal/storage"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// slack absorbs the rounding of summed budgets, so ten sessions spending 0.1
+// fit into a budget of 1.
+const slack = 1e-9
+
+// budgetKey identifies the budget of a participant on a dataset.
+type budgetKey struct {
+	participant string
+	dataset     string
+}
+
+// spending is what a participant spent on a dataset across sessions.
+type spending struct {
+	epsilon, delta float64
+	sessions       int
+}
+
+// charge adds the cost of a session to the ledger of every participant.
+// Callers must hold s.mu.
+func (s *server) charge(participants []string, dataset string, epsilon, delta float64) {
+	for _, p := range participants {
+		spent := s.ledger[budgetKey{p, dataset}]
+		spent.epsilon += epsilon
+		spent.delta += delta
+		spent.sessions++
+		s.ledger[budgetKey{p, dataset}] = spent
+	}
+}
+
+// takePart charges a session with noise to participant the first time they
+// take part in it. Listing a participant in a session spends nothing of their
+// budget, so no participant can drain another's by creating sessions. Callers
+// must hold s.mu.
+func (s *server) takePart(id string, sess *session, participant string) error {
+	if !sess.privacy.Enabled() || sess.charged[participant] {
+		return nil
+	}
+	if err := s.checkLedger([]string{participant}, sess.dataset, sess.privacy); err != nil {
+		return err
+	}
+	epsilon, delta := sess.privacy.Cost()
+	return s.record(storage.Record{Kind: storage.KindSpend, Session: id, Instance: sess.instance, Participants: []string{participant}, Dataset: sess.dataset, Epsilon: epsilon, Delta: delta})
+}
+
+// callerTakesPart charges the session to the participants the caller's
+// certificate was issued for. Callers must hold s.mu.
+func (s *server) callerTakesPart(ctx context.Context, id string, sess *session) error {
+	names, err := callerIdentities(ctx)
+	if err != nil {
+		// Admins and callers the interceptor did not check take no part
+		return nil
+	}
+	for _, name := range names {
+		if sess.hasParticipant(name) {
+			if err := s.takePart(id, sess, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkExact refuses to deal triples or bit masks for, or to pass openings
+// through, a session with noise or on a named dataset. Participants could
+// otherwise open their shares of the inputs and reconstruct the exact
+// aggregate, bypassing both the noise and the ledger.
+func (sess *session) checkExact(id string) error {
+	if sess.privacy.Enabled() || sess.dataset != "" {
+		return status.Errorf(codes.FailedPrecondition, "session %q releases only noisy results, it cannot open values", id)
+	}
+	return nil
+}
+
+// checkLedger makes sure a new session on dataset keeps every participant
+// within the privacy budget the server grants per dataset across sessions.
+// A session without noise releases exact results, so on a named dataset it
+// costs an unbounded budget and is always refused. Sessions naming no dataset
+// query dataset "", which only binds sessions without noise when the server
+// requires noise; otherwise their exact results stay outside the ledger.
+// Callers must hold s.mu.
+func (s *server) checkLedger(participants []string, dataset string, privacy dp.Params) error {
+	if !privacy.Enabled() {
+		if s.privacy.Required {
+			return status.Error(codes.FailedPrecondition, "this server only accepts sessions with differential privacy noise")
+		}
+		if dataset == "" {
+			return nil
+		}
+		return status.Errorf(codes.ResourceExhausted, "session without noise would release exact results of dataset %q, beyond any privacy budget", dataset)
+	}
+	epsilon, delta := privacy.Cost()
+	var exhausted []string
+	for _, p := range participants {
+		spent := s.ledger[budgetKey{p, dataset}]
+		if spent.epsilon+epsilon > s.privacy.TotalEpsilon+slack || spent.delta+delta > s.privacy.TotalDelta+slack {
+			exhausted = append(exhausted, p)
+		}
+	}
+	if len(exhausted) > 0 {
+		return status.Errorf(codes.ResourceExhausted, "session would take %s beyond the budget of epsilon %v and delta %v on dataset %q",
+			strings.Join(exhausted, ", "), s.privacy.TotalEpsilon, s.privacy.TotalDelta, dataset)
+	}
+	return nil
+}
+
+// GetBudget returns the privacy budget spent per participant and dataset,
+// limited to req.Participant and req.Dataset when they are set. Entries are
+// sorted by participant and dataset.
+func (s *server) GetBudget(ctx context.Context, req *pb.GetBudgetRequest) (*pb.GetBudgetResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	response := &pb.GetBudgetResponse{}
+	for key, spent := range s.ledger {
+		if (req.Participant != "" && key.participant != req.Participant) || (req.Dataset != "" && key.dataset != req.Dataset) {
+			continue
+		}
+		response.Budgets = append(response.Budgets, &pb.Budget{
+			Participant: key.participant,
+			Dataset:     key.dataset,
+			Epsilon:     spent.epsilon,
+			Delta:       spent.delta,
+			Sessions:    int32(spent.sessions),
+			MaxEpsilon:  s.privacy.TotalEpsilon,
+			MaxDelta:    s.privacy.TotalDelta,
+		})
+	}
+	slices.SortFunc(response.Budgets, func(a, b *pb.Budget) int {
+		return cmp.Or(cmp.Compare(a.Participant, b.Participant), cmp.Compare(a.Dataset, b.Dataset))
+	})
+	return response, nil
+}
@@ -0,0 +1,291 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "hospital/api"
+	"hospital/internal/config"
+	"hospital/internal/storage"
+
+	"google.golang.org/grpc/codes"
+)
+
+var testBudget = config.PrivacyConfig{MaxEpsilon: 1, MaxDelta: 1e-5, TotalEpsilon: 1, TotalDelta: 1e-4}
+
+// takePart creates or joins a session with laplace noise of epsilon as every
+// participant in turn.
+func takePart(s *server, id, dataset string, epsilon float64, participants ...string) error {
+	for _, p := range participants {
+		if err := createSessionAs(s, p, id, dataset, epsilon, participants...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendShare sends a share of 1 from one participant of a single value
+// session to another.
+func sendShare(s *server, id, from, to string) error {
+	_, err := s.SendShare(context.Background(), &pb.Share{Session: id, From: from, To: to, Seq: 1, Parts: [][]byte{s.field.Bytes(s.field.Int(1))}})
+	return err
+}
+
+func TestLedgerRefusesExhaustedBudget(t *testing.T) {
+	s := newTestServer(t, storage.NewMemory(), testBudget)
+
+	for _, id := range []string{"s1", "s2"} {
+		if err := takePart(s, id, "icu", 0.4, "Alice", "Bob"); err != nil {
+			t.Fatalf("session %s: %v", id, err)
+		}
+	}
+	// Joining a session again is not charged again
+	if err := takePart(s, "s2", "icu", 0.4, "Alice", "Bob"); err != nil {
+		t.Fatalf("joining s2 again: %v", err)
+	}
+	wantCode(t, takePart(s, "s3", "icu", 0.4, "Alice", "Bob"), codes.ResourceExhausted)
+	wantCode(t, takePart(s, "s3", "icu", 0.4, "Alice", "Charlie"), codes.ResourceExhausted)
+
+	// Other participants and datasets have their own budgets
+	if err := takePart(s, "s4", "icu", 0.2, "Alice", "Charlie"); err != nil {
+		t.Fatalf("budget of 1 rejected 0.8 + 0.2: %v", err)
+	}
+	if err := takePart(s, "s5", "labs", 1, "Alice", "Bob"); err != nil {
+		t.Fatalf("another dataset: %v", err)
+	}
+
+	r, err := s.GetBudget(context.Background(), &pb.GetBudgetRequest{Dataset: "icu"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]float64{"Alice": 1, "Bob": 0.8, "Charlie": 0.2}
+	if len(r.Budgets) != len(want) {
+		t.Fatalf("got %d budgets on icu, want %d", len(r.Budgets), len(want))
+	}
+	for i, b := range r.Budgets {
+		if i > 0 && r.Budgets[i-1].Participant >= b.Participant {
+			t.Errorf("budgets not sorted by participant: %v", r.Budgets)
+		}
+		if diff := b.Epsilon - want[b.Participant]; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("%s spent epsilon %v on icu, want %v", b.Participant, b.Epsilon, want[b.Participant])
+		}
+		if b.MaxEpsilon != testBudget.TotalEpsilon {
+			t.Errorf("%s has limit %v, want %v", b.Participant, b.MaxEpsilon, testBudget.TotalEpsilon)
+		}
+	}
+}
+
+func TestLedgerChargesOnlyParticipantsTakingPart(t *testing.T) {
+	s := newTestServer(t, storage.NewMemory(), testBudget)
+	spent := func(participant string) float64 {
+		t.Helper()
+		r, err := s.GetBudget(context.Background(), &pb.GetBudgetRequest{Participant: participant, Dataset: "icu"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r.Budgets) == 0 {
+			return 0
+		}
+		return r.Budgets[0].Epsilon
+	}
+
+	// Alice tries to drain Bob's budget with sessions Bob never joins. Her
+	// own session is charged to her only, and once she is out of budget she
+	// cannot create any more.
+	if err := createSessionAs(s, "Alice", "drain-0", "icu", 1, "Alice", "Bob"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < 5; i++ {
+		wantCode(t, createSessionAs(s, "Alice", fmt.Sprint("drain-", i), "icu", 1, "Alice", "Bob"), codes.ResourceExhausted)
+	}
+	// Sessions created on nobody's behalf are charged to nobody
+	for i := range 5 {
+		if err := createSession(s, fmt.Sprint("listed-", i), "icu", 1, "Bob", "Charlie"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := spent("Alice"); got != 1 {
+		t.Errorf("Alice spent %v, want 1", got)
+	}
+	if got := spent("Bob"); got != 0 {
+		t.Fatalf("Bob spent %v without taking part, want 0", got)
+	}
+
+	// Bob is charged once he sends his first share, and only once
+	for range 2 {
+		if err := sendShare(s, "listed-0", "Bob", "Charlie"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := spent("Bob"); got != 1 {
+		t.Errorf("Bob spent %v after sharing, want 1", got)
+	}
+	wantCode(t, sendShare(s, "listed-1", "Bob", "Charlie"), codes.ResourceExhausted)
+}
+
+func TestLedgerRefusesSessionsWithoutNoise(t *testing.T) {
+	s := newTestServer(t, storage.NewMemory(), testBudget)
+
+	// Exact results of a named dataset are beyond any budget, spent or not
+	wantCode(t, createSession(s, "exact", "icu", 0, "Alice", "Bob"), codes.ResourceExhausted)
+	if err := createSession(s, "noisy", "icu", 1, "Alice", "Bob"); err != nil {
+		t.Fatal(err)
+	}
+	wantCode(t, createSession(s, "exact", "icu", 0, "Alice", "Bob"), codes.ResourceExhausted)
+
+	// Sessions on no dataset stay outside the ledger
+	if err := createSession(s, "unnamed", "", 0, "Alice", "Bob"); err != nil {
+		t.Fatalf("session without dataset: %v", err)
+	}
+}
+
+func TestLedgerRequiredCoversUnnamedDataset(t *testing.T) {
+	budget := testBudget
+	budget.Required = true
+	s := newTestServer(t, storage.NewMemory(), budget)
+
+	// Without a dataset exact results are refused as well, and noise is
+	// charged to dataset ""
+	wantCode(t, createSession(s, "exact", "", 0, "Alice", "Bob"), codes.FailedPrecondition)
+	if err := takePart(s, "noisy", "", 1, "Alice", "Bob"); err != nil {
+		t.Fatal(err)
+	}
+	wantCode(t, takePart(s, "again", "", 1, "Alice", "Bob"), codes.ResourceExhausted)
+}
+
+func TestNoisySessionsRefuseOpenings(t *testing.T) {
+	s := newTestServer(t, storage.NewMemory(), testBudget)
+	if err := createSession(s, "noisy", "", 1, "Alice", "Bob"); err != nil {
+		t.Fatal(err)
+	}
+	if err := createSession(s, "exact", "", 0, "Alice", "Bob"); err != nil {
+		t.Fatal(err)
+	}
+	open := func(id string) error {
+		_, err := s.SendOpening(context.Background(), &pb.Opening{Session: id, From: "Alice", To: "Bob", Seq: 1, Round: "open", Values: [][]byte{s.field.Bytes(s.field.Int(1))}})
+		return err
+	}
+
+	// Opening shares of the inputs would release them without the noise
+	wantCode(t, open("noisy"), codes.FailedPrecondition)
+	_, err := s.GetTriples(context.Background(), &pb.GetTriplesRequest{Session: "noisy", Participant: "Alice", Count: 1})
+	wantCode(t, err, codes.FailedPrecondition)
+	_, err = s.GetBitMasks(context.Background(), &pb.GetBitMasksRequest{Session: "noisy", Participant: "Alice", Count: 1})
+	wantCode(t, err, codes.FailedPrecondition)
+
+	if err := open("exact"); err != nil {
+		t.Fatalf("opening in a session without noise: %v", err)
+	}
+}
+
+func TestLedgerSurvivesRestart(t *testing.T) {
+	store := storage.NewMemory()
+	s := newTestServer(t, store, testBudget)
+	if err := takePart(s, "s1", "icu", 0.6, "Alice", "Bob"); err != nil {
+		t.Fatal(err)
+	}
+	// The restored session remembers who it was charged to
+	s = newTestServer(t, store, testBudget)
+	if err := takePart(s, "s1", "icu", 0.6, "Alice", "Bob"); err != nil {
+		t.Fatalf("joining s1 after restart: %v", err)
+	}
+	if _, err := s.CloseSession(context.Background(), &pb.CloseSessionRequest{Session: "s1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Restoring compacts the closed session away but keeps what it spent
+	for range 2 {
+		s = newTestServer(t, store, testBudget)
+	}
+	r, err := s.GetBudget(context.Background(), &pb.GetBudgetRequest{Participant: "Bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Budgets) != 1 || r.Budgets[0].Epsilon != 0.6 || r.Budgets[0].Sessions != 1 {
+		t.Fatalf("Bob's budget after restart = %v, want epsilon 0.6 in 1 session", r.Budgets)
+	}
+	wantCode(t, createSession(s, "s2", "icu", 0.6, "Alice", "Bob"), codes.ResourceExhausted)
+}
+
+func TestLedgerChargesReusedSessionIDAfterRestart(t *testing.T) {
+	sessions, ledger := storage.NewMemory(), storage.NewMemory()
+	s := newTestServerWithLedger(t, sessions, ledger, testBudget)
+	if err := takePart(s, "demo", "icu", 0.4, "Alice", "Bob"); err != nil {
+		t.Fatal(err)
+	}
+	for _, caller := range []string{"Alice", "Bob"} {
+		if _, err := s.CloseSession(as(caller), &pb.CloseSessionRequest{Session: "demo"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := createSessionAs(s, "Alice", "demo", "icu", 0.4, "Alice", "Bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	// What Bob spent on the first "demo" does not count for the second
+	s = newTestServerWithLedger(t, sessions, ledger, testBudget)
+	if err := createSessionAs(s, "Bob", "demo", "icu", 0.4, "Alice", "Bob"); err != nil {
+		t.Fatal(err)
+	}
+	r, err := s.GetBudget(context.Background(), &pb.GetBudgetRequest{Dataset: "icu"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range r.Budgets {
+		if b.Epsilon != 0.8 || b.Sessions != 2 {
+			t.Errorf("%s spent epsilon %v in %d sessions, want 0.8 in 2", b.Participant, b.Epsilon, b.Sessions)
+		}
+	}
+}
+
+func TestLedgerSurvivesRestartWithDefaultConfig(t *testing.T) {
+	policy, err := filepath.Abs(filepath.Join("..", "..", "config", "policy.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	// The sessions live in memory only, the ledger does not
+	cfg := config.Default()
+	cfg.PolicyFile = policy
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	start := func() *server {
+		t.Helper()
+		s, err := newService(cfg, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	s := start()
+	for i := range int(cfg.Privacy.TotalEpsilon) {
+		if err := takePart(s, fmt.Sprint("s", i), "icu", cfg.Privacy.MaxEpsilon, "Alice", "Bob"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s = start()
+	defer s.close()
+	wantCode(t, createSession(s, "more", "icu", cfg.Privacy.MaxEpsilon, "Alice", "Bob"), codes.ResourceExhausted)
+
+	cfg.Privacy.LedgerFile = ""
+	if _, err := newService(cfg, ""); err == nil {
+		t.Error("started without a ledger file")
+	}
+}
@@ -0,0 +1,211 @@
+package server
+
+import (
+	"context"
+	"log"
+	"math/big"
+
+	pb "hospital/api"
+	"hospital/internal/storage"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxTriples bounds the Beaver triples dealt per session, so a participant
+// cannot make the server generate and store unbounded randomness.
+const maxTriples = 1 << 16
+
+// opening collects the shares participants send of a value they all learn,
+// such as the masked inputs of a multiplication.
+type opening struct {
+	length int                        // elements of every share
+	from   map[string]map[string]bool // senders whose share was added, per recipient
+	sums   map[string][]*big.Int      // element-wise sum of the shares, per recipient
+}
+
+func newOpening(length int) *opening {
+	return &opening{
+		length: length,
+		from:   make(map[string]map[string]bool),
+		sums:   make(map[string][]*big.Int),
+	}
+}
+
+// dealt returns the number of Beaver triples handed out in the session.
+func (sess *session) dealt() int {
+	return len(sess.triples[sess.participants[0]]) / 3
+}
+
+// GetTriples returns req.Participant's shares of the Beaver triples numbered
+// req.Offset up to req.Offset+req.Count. The server acts as trusted dealer: it
+// draws random a and b and shares a, b and c = a*b additively among the
+// participants. Triples are dealt once, so every participant asking for the
+// same numbers gets its share of the same triples.
+//
+// The dealer stores every participant's shares, so it knows every triple. In
+// central mode the openings of a multiplication pass through it as well, and
+// it can unmask them and learn the multiplied inputs. The server must
+// therefore be trusted with them; in peer-to-peer mode it only sees the
+// triples, not the openings.
+func (s *server) GetTriples(ctx context.Context, req *pb.GetTriplesRequest) (*pb.GetTriplesResponse, error) {
+	if err := s.checkOwner(req.Participant); err != nil {
+		return nil, err
+	}
+	end := uint64(req.Offset) + uint64(req.Count)
+	if req.Count == 0 || end > maxTriples {
+		return nil, status.Errorf(codes.InvalidArgument, "triples [%d, %d) out of range [0, %d)", req.Offset, end, maxTriples)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, err := s.lookupSession(req.Session)
+	if err != nil {
+		return nil, err
+	}
+	if !sess.hasParticipant(req.Participant) {
+		return nil, status.Errorf(codes.InvalidArgument, "%q is not a participant of this session", req.Participant)
+	}
+	if err := sess.checkExact(req.Session); err != nil {
+		return nil, err
+	}
+	if sess.scheme != pb.Scheme_ADDITIVE {
+		return nil, status.Errorf(codes.FailedPrecondition, "session %q uses %s sharing, triples need additive sharing", req.Session, sess.scheme)
+	}
+
+	if missing := int(end) - sess.dealt(); missing > 0 {
+		values, err := s.dealTriples(missing, len(sess.participants))
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "could not generate triples: %v", err)
+		}
+		if err := s.record(storage.Record{Kind: storage.KindTriples, Session: req.Session, Values: values}); err != nil {
+			return nil, err
+		}
+		log.Printf("Session %s: dealt %d triples", req.Session, missing)
+	}
+
+	shares := sess.triples[req.Participant][3*req.Offset : 3*end]
+	response := &pb.GetTriplesResponse{}
+	for i := 0; i < len(shares); i += 3 {
+		response.Triples = append(response.Triples, &pb.Triple{
+			A: s.field.Bytes(shares[i]),
+			B: s.field.Bytes(shares[i+1]),
+			C: s.field.Bytes(shares[i+2]),
+		})
+	}
+	return response, nil
+}
+
+// dealTriples generates count Beaver triples shared among n participants. For
+// every triple in turn it returns each participant's shares of a, b and c,
+// the layout of a KindTriples record.
+func (s *server) dealTriples(count, n int) ([]*big.Int, error) {
+	values := make([]*big.Int, 0, 3*n*count)
+	for range count {
+		a, err := s.field.Random()
+		if err != nil {
+			return nil, err
+		}
+		b, err := s.field.Random()
+		if err != nil {
+			return nil, err
+		}
+		shares, err := s.field.ShareVector([]*big.Int{a, b, s.field.Mul(a, b)}, n)
+		if err != nil {
+			return nil, err
+		}
+		for _, share := range shares {
+			values = append(values, share...)
+		}
+	}
+	return values, nil
+}
+
+// SendOpening receives a participant's share of a value being opened. A share
+// that was already delivered is acknowledged again without being added twice.
+func (s *server) SendOpening(ctx context.Context, o *pb.Opening) (*pb.Ack, error) {
+	if o.Round == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing round")
+	}
+	if len(o.Values) == 0 || len(o.Values) > maxLength {
+		return nil, status.Errorf(codes.InvalidArgument, "opening of %d elements out of range [1, %d]", len(o.Values), maxLength)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkOwner(o.To); err != nil {
+		return nil, err
+	}
+	sess, err := s.lookupSession(o.Session)
+	if err != nil {
+		return nil, err
+	}
+	if err := sess.checkExact(o.Session); err != nil {
+		return nil, err
+	}
+	values, err := s.elements(o.Values)
+	if err != nil {
+		return nil, err
+	}
+	rec := storage.Record{Kind: storage.KindOpening, Session: o.Session, From: o.From, To: o.To, Seq: o.Seq, Round: o.Round, Values: values}
+	duplicate, err := s.checkMessage(sess, rec)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate {
+		log.Printf("Session %s: ignoring duplicate opening %d from %s", o.Session, o.Seq, o.From)
+		return &pb.Ack{Message: "Opening already received"}, nil
+	}
+	if round, ok := sess.openings[o.Round]; ok {
+		if len(o.Values) != round.length {
+			return nil, status.Errorf(codes.InvalidArgument, "opening from %s has %d elements, round %q opens %d", o.From, len(o.Values), o.Round, round.length)
+		}
+		if round.from[o.To][o.From] {
+			return nil, status.Errorf(codes.AlreadyExists, "opening %q from %s to %s already received", o.Round, o.From, o.To)
+		}
+	}
+	if err := s.takePart(o.Session, sess, o.From); err != nil {
+		return nil, err
+	}
+
+	if err := s.record(rec); err != nil {
+		return nil, err
+	}
+	log.Printf("Session %s: received opening %q from %s to %s", o.Session, o.Round, o.From, o.To)
+
+	return &pb.Ack{Message: "Opening received"}, nil
+}
+
+// GetOpened waits until every other participant has sent its share of the
+// round to req.Participant and returns their sum. Adding its own share gives
+// the participant the opened value.
+func (s *server) GetOpened(ctx context.Context, req *pb.GetOpenedRequest) (*pb.GetOpenedResponse, error) {
+	if err := s.checkOwner(req.Participant); err != nil {
+		return nil, err
+	}
+	if err := s.checkParticipant(req.Session, req.Participant); err != nil {
+		return nil, err
+	}
+	if req.Round == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing round")
+	}
+
+	var sum []*big.Int
+	var received, expected int
+	err := s.waitUntil(ctx, req.Session, func(sess *session) bool {
+		expected = sess.expected()
+		round, ok := sess.openings[req.Round]
+		if !ok {
+			return false
+		}
+		sum, received = round.sums[req.Participant], len(round.from[req.Participant])
+		return received >= expected
+	})
+	if err != nil {
+		return nil, waitError(err, "openings of "+req.Round, req.Participant, received, expected)
+	}
+
+	return &pb.GetOpenedResponse{Values: s.field.Marshal(sum)}, nil
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Rule names a condition under which a caller may invoke a method.
+type Rule string
+
+const (
+	// RuleSelf allows callers acting as themselves: the request's From or
+	// Participant field must be one of the caller's certificate names.
+	RuleSelf Rule = "self"
+	// RuleParticipant allows callers taking part in the session the request
+	// is about.
+	RuleParticipant Rule = "participant"
+	// RuleAdmin allows the identities listed as admins.
+	RuleAdmin Rule = "admin"
+	// RuleAny allows every authenticated caller.
+	RuleAny Rule = "any"
+)
+
+// Policy decides which callers may invoke which methods. A call is allowed
+// when any rule listed for its method matches; methods without rules are
+// denied.
+type Policy struct {
+	Admins  []string          `json:"admins"`
+	Methods map[string][]Rule `json:"methods"` // keyed by method name, e.g. "GetAddedShares"
+}
+
+// LoadPolicy reads a JSON policy file.
+func LoadPolicy(file string) (*Policy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", file, err)
+	}
+	for method, rules := range p.Methods {
+		for _, r := range rules {
+			switch r {
+			case RuleSelf, RuleParticipant, RuleAdmin, RuleAny:
+			default:
+				return nil, fmt.Errorf("invalid policy %s: unknown rule %q for %s", file, r, method)
+			}
+		}
+	}
+	return &p, nil
+}
+
+// authorize checks a call to fullMethod by a caller holding a certificate for
+// names. participants returns who takes part in the session the request is
+// about.
+func (p *Policy) authorize(fullMethod string, names []string, req any, participants func(req any) []string) error {
+	method := path.Base(fullMethod)
+	for _, r := range p.Methods[method] {
+		if p.matches(r, names, req, participants) {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "%v may not call %s", names, method)
+}
+
+func (p *Policy) matches(r Rule, names []string, req any, participants func(req any) []string) bool {
+	switch r {
+	case RuleAny:
+		return true
+	case RuleAdmin:
+		return containsAny(p.Admins, names)
+	case RuleSelf:
+		claimed, ok := claimedIdentity(req)
+		return ok && slices.Contains(names, claimed)
+	case RuleParticipant:
+		return containsAny(participants(req), names)
+	}
+	return false
+}
+
+func containsAny(list, names []string) bool {
+	for _, name := range names {
+		if slices.Contains(list, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "hospital/api"
+	"hospital/internal/config"
+	"hospital/internal/storage"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDefaultPolicyCoversService(t *testing.T) {
+	p, err := LoadPolicy(filepath.Join("..", "..", "config", "policy.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A method missing from the policy is denied to everyone
+	for _, m := range pb.SecretSharingService_ServiceDesc.Methods {
+		if len(p.Methods[m.MethodName]) == 0 {
+			t.Errorf("policy has no rules for %s", m.MethodName)
+		}
+	}
+}
+
+func TestPolicyAuthorize(t *testing.T) {
+	p := &Policy{
+		Admins: []string{"Admin"},
+		Methods: map[string][]Rule{
+			"SendShare":     {RuleSelf},
+			"CreateSession": {RuleParticipant, RuleAdmin},
+			"GetBudget":     {RuleAdmin, RuleSelf},
+			"GetAggregate":  {RuleAny},
+		},
+	}
+	participants := func(req any) []string {
+		if r, ok := req.(*pb.CreateSessionRequest); ok {
+			return r.Participants
+		}
+		return nil
+	}
+	create := &pb.CreateSessionRequest{Participants: []string{"Alice", "Bob"}}
+
+	tests := []struct {
+		name   string
+		method string
+		caller []string
+		req    any
+		want   codes.Code
+	}{
+		{"share as self", "SendShare", []string{"Alice"}, &pb.Share{From: "Alice"}, codes.OK},
+		{"share as another", "SendShare", []string{"Alice"}, &pb.Share{From: "Bob"}, codes.PermissionDenied},
+		{"share by admin as another", "SendShare", []string{"Admin"}, &pb.Share{From: "Bob"}, codes.PermissionDenied},
+		{"second certificate name", "SendShare", []string{"Hospital A", "Alice"}, &pb.Share{From: "Alice"}, codes.OK},
+		{"create as participant", "CreateSession", []string{"Bob"}, create, codes.OK},
+		{"create as outsider", "CreateSession", []string{"Charlie"}, create, codes.PermissionDenied},
+		{"create as admin", "CreateSession", []string{"Admin"}, create, codes.OK},
+		{"own budget", "GetBudget", []string{"Alice"}, &pb.GetBudgetRequest{Participant: "Alice"}, codes.OK},
+		{"other budget", "GetBudget", []string{"Alice"}, &pb.GetBudgetRequest{Participant: "Bob"}, codes.PermissionDenied},
+		{"all budgets", "GetBudget", []string{"Alice"}, &pb.GetBudgetRequest{}, codes.PermissionDenied},
+		{"all budgets as admin", "GetBudget", []string{"Admin"}, &pb.GetBudgetRequest{}, codes.OK},
+		{"any", "GetAggregate", []string{"Charlie"}, &pb.GetAggregateRequest{}, codes.OK},
+		{"unlisted method", "CloseSession", []string{"Admin"}, &pb.CloseSessionRequest{}, codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.authorize("/SecretSharingService/"+tt.method, tt.caller, tt.req, participants)
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("got %v (%v), want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPolicyRejectsUnknownRule(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(file, []byte(`{"methods": {"SendShare": ["everyone"]}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPolicy(file); err == nil {
+		t.Error("policy with an unknown rule loaded")
+	}
+}
+
+func TestInterceptorBindsIdentity(t *testing.T) {
+	s := newTestServer(t, storage.NewMemory(), config.PrivacyConfig{})
+	s.policy = &Policy{Admins: []string{"Admin"}, Methods: map[string][]Rule{"SendShare": {RuleAny}, "GetBudget": {RuleAdmin}}}
+	info := func(method string) *grpc.UnaryServerInfo {
+		return &grpc.UnaryServerInfo{FullMethod: "/SecretSharingService/" + method}
+	}
+	handler := func(ctx context.Context, req any) (any, error) { return &pb.Ack{}, nil }
+
+	// Even a policy allowing anyone cannot let Alice or an admin act as Bob
+	for _, caller := range []string{"Alice", "Admin"} {
+		_, err := s.authInterceptor(as(caller), &pb.Share{From: "Bob"}, info("SendShare"), handler)
+		wantCode(t, err, codes.PermissionDenied)
+	}
+	if _, err := s.authInterceptor(as("Alice"), &pb.Share{From: "Alice"}, info("SendShare"), handler); err != nil {
+		t.Fatal(err)
+	}
+	// Reading another participant's budget is up to the policy
+	if _, err := s.authInterceptor(as("Admin"), &pb.GetBudgetRequest{Participant: "Bob"}, info("GetBudget"), handler); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAggregatesOnlyForParticipants(t *testing.T) {
+	p, err := LoadPolicy(filepath.Join("..", "..", "config", "policy.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestServer(t, storage.NewMemory(), config.PrivacyConfig{})
+	s.policy = p
+	if err := createSession(s, "s1", "", 0, "Alice", "Bob"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.PublishAggregate(context.Background(), &pb.Aggregate{Session: "s1", From: "Alice", Values: [][]byte{s.field.Bytes(s.field.Int(1))}}); err != nil {
+		t.Fatal(err)
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/SecretSharingService/GetAggregate"}
+	read := func(caller string) error {
+		_, err := s.authInterceptor(as(caller), &pb.GetAggregateRequest{Session: "s1"}, info, func(ctx context.Context, req any) (any, error) {
+			return s.GetAggregate(ctx, req.(*pb.GetAggregateRequest))
+		})
+		return err
+	}
+
+	wantCode(t, read("Charlie"), codes.PermissionDenied)
+	if err := read("Bob"); err != nil {
+		t.Fatal(err)
+	}
+	// Once closed, those who published can still read the results
+	if _, err := s.CloseSession(context.Background(), &pb.CloseSessionRequest{Session: "s1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := read("Alice"); err != nil {
+		t.Fatal(err)
+	}
+	wantCode(t, read("Charlie"), codes.PermissionDenied)
+}
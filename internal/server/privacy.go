@@ -0,0 +1,50 @@
+package server
+
+import (
+	pb "hospital/api"
+	"hospital/internal/dp"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mechanisms maps the noise of a CreateSession request to its mechanism.
+var mechanisms = map[pb.Mechanism]dp.Mechanism{
+	pb.Mechanism_NO_NOISE: dp.None,
+	pb.Mechanism_LAPLACE:  dp.Laplace,
+	pb.Mechanism_GAUSSIAN: dp.Gaussian,
+}
+
+// privacyParams validates the noise a session asks for.
+func privacyParams(p *pb.Privacy) (dp.Params, error) {
+	if p == nil || p.Mechanism == pb.Mechanism_NO_NOISE {
+		return dp.Params{}, nil
+	}
+	mechanism, ok := mechanisms[p.Mechanism]
+	if !ok {
+		return dp.Params{}, status.Errorf(codes.InvalidArgument, "unknown noise mechanism %v", p.Mechanism)
+	}
+	params := dp.Params{Mechanism: mechanism, Epsilon: p.Epsilon, Delta: p.Delta, Sensitivity: p.Sensitivity}
+	if mechanism == dp.Laplace {
+		params.Delta = 0
+	}
+	if err := params.Validate(); err != nil {
+		return dp.Params{}, status.Errorf(codes.InvalidArgument, "invalid privacy parameters: %v", err)
+	}
+	return params, nil
+}
+
+// checkBudget makes sure a session's release stays within the privacy budget
+// the server grants every session. Sessions without noise are left to
+// checkLedger.
+func (s *server) checkBudget(privacy dp.Params) error {
+	if !privacy.Enabled() {
+		return nil
+	}
+	epsilon, delta := privacy.Cost()
+	if epsilon > s.privacy.MaxEpsilon || delta > s.privacy.MaxDelta {
+		return status.Errorf(codes.ResourceExhausted, "session would spend epsilon %v and delta %v, the budget per session is epsilon %v and delta %v",
+			epsilon, delta, s.privacy.MaxEpsilon, s.privacy.MaxDelta)
+	}
+	return nil
+}
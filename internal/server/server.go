@@ -2,114 +2,658 @@ package server
 
 import (
 	"context"
-	"crypto/tls"
+	"fmt"
 	"log"
+	"math/big"
 	"net"
+	"slices"
 	"sync"
+	"time"
 
 	pb "hospital/api"
+	"hospital/internal/config"
+	"hospital/internal/pki"
+	"hospital/internal/sharing"
+	"hospital/internal/storage"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
 )
 
+// maxLength bounds the vectors of a session, so a single request cannot make
+// the server allocate unbounded totals.
+const maxLength = 1 << 16
+
+// maxPublished bounds the sessions whose published results the server keeps.
+// Once more sessions published, the results of the oldest are dropped.
+const maxPublished = 1 << 12
+
+// maxOpenSessions bounds the sessions a caller created and has not left yet,
+// so no participant can make the server keep unbounded state.
+const maxOpenSessions = 64
+
+// compactAfter is the number of records of dropped sessions and results the
+// store may hold before it is rewritten without them. Rewriting it on every
+// drop would block all calls for the time it takes.
+const compactAfter = 1 << 10
+
 // server is used to implement secretsharing.SecretSharingServiceServer
 type server struct {
 	pb.UnimplementedSecretSharingServiceServer
-	receivedShares map[string]int64 // key is the participant and the value is the part
-	outShares      map[string]int64
-	mu             sync.RWMutex // Use RWMutex for more granular locking
+	sessions map[string]*session // key is the session id
+	field    *sharing.Field      // prime field the shares are summed in
+	mu       sync.RWMutex        // Use RWMutex for more granular locking
+	policy   *Policy             // who may call which method
+	store    storage.Store       // durable record of the state changes
+	// ledgerStore records what the ledger was charged. The central server
+	// keeps it apart from store, so budgets outlive the sessions.
+	ledgerStore storage.Store
+
+	// owner is set when the server is a participant's own endpoint in
+	// peer-to-peer mode. It then only accepts shares addressed to the owner.
+	owner string
+	// published holds the final results participants published, keyed by
+	// session and participant, for the last maxPublished sessions in the
+	// order they first published.
+	published      map[string]map[string][]*big.Int
+	publishedOrder []string
+	// dead counts the records of dropped sessions and results still in
+	// store. It is compacted once there are compactAfter of them.
+	dead         int
+	compactAfter int
+
+	// waitTimeout bounds how long GetAddedShares and GetAddedOut wait for
+	// missing contributions.
+	waitTimeout time.Duration
+	// privacy bounds the differential privacy budget of every session.
+	privacy config.PrivacyConfig
+	// ledger holds the privacy budget spent per participant and dataset
+	// across sessions, including closed ones.
+	ledger map[budgetKey]spending
 }
 
-// SendShare receives a Share message
+// SendShare receives a Share message. A share that was already delivered
+// is acknowledged again without being added twice.
 func (s *server) SendShare(ctx context.Context, share *pb.Share) (*pb.Ack, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, exists := s.receivedShares[share.To]; !exists {
-		s.receivedShares[share.To] = 0
+	if err := s.checkOwner(share.To); err != nil {
+		return nil, err
+	}
+	sess, err := s.lookupSession(share.Session)
+	if err != nil {
+		return nil, err
+	}
+	parts, err := s.elements(share.Parts)
+	if err != nil {
+		return nil, err
+	}
+	rec := storage.Record{Kind: storage.KindShare, Session: share.Session, From: share.From, To: share.To, Seq: share.Seq, Values: parts}
+	duplicate, err := s.checkMessage(sess, rec)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate {
+		log.Printf("Session %s: ignoring duplicate share %d from %s", share.Session, share.Seq, share.From)
+		return &pb.Ack{Message: "Share already received"}, nil
+	}
+	if err := sess.checkLength(share.From, len(share.Parts)); err != nil {
+		return nil, err
+	}
+	if sess.shareFrom[share.To][share.From] {
+		return nil, status.Errorf(codes.AlreadyExists, "share from %s to %s already received", share.From, share.To)
+	}
+	if err := s.takePart(share.Session, sess, share.From); err != nil {
+		return nil, err
+	}
+
+	if err := s.record(rec); err != nil {
+		return nil, err
 	}
-	s.receivedShares[share.To] += share.Part
-	log.Printf("Updated receivedShares for %s: %d", share.To, s.receivedShares[share.To])
+	log.Printf("Session %s: updated receivedShares for %s: %d", share.Session, share.To, sess.receivedShares[share.To])
 
 	return &pb.Ack{Message: "Share received"}, nil
 }
 
 func (s *server) SendShareOut(ctx context.Context, share *pb.ShareOut) (*pb.Ack, error) {
 	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	if _, exists := s.outShares[share.To]; !exists {
-		s.outShares[share.To] = 0
+	if err := s.checkOwner(share.To); err != nil {
+		return nil, err
+	}
+	sess, err := s.lookupSession(share.Session)
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.elements(share.Data)
+	if err != nil {
+		return nil, err
+	}
+	rec := storage.Record{Kind: storage.KindOut, Session: share.Session, From: share.From, To: share.To, Seq: share.Seq, Values: data}
+	duplicate, err := s.checkMessage(sess, rec)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate {
+		log.Printf("Session %s: ignoring duplicate out share %d from %s", share.Session, share.Seq, share.From)
+		return &pb.Ack{Message: "Out already received"}, nil
+	}
+	if err := sess.checkLength(share.From, len(share.Data)); err != nil {
+		return nil, err
+	}
+	if _, exists := sess.outFrom[share.To][share.From]; exists {
+		return nil, status.Errorf(codes.AlreadyExists, "out share from %s to %s already received", share.From, share.To)
+	}
+	if err := s.takePart(share.Session, sess, share.From); err != nil {
+		return nil, err
 	}
-	s.outShares[share.To] += share.Data
-
-	s.mu.Unlock()
 
-	log.Printf("Received out share from %s to %s with value %d", share.From, share.To, share.Data)
+	released := sess.released
+	if err := s.record(rec); err != nil {
+		return nil, err
+	}
+	log.Printf("Session %s: received out share from %s to %s with value %d", share.Session, share.From, share.To, data)
+	if !released && sess.privacy.Enabled() {
+		epsilon, delta := sess.privacy.Cost()
+		log.Printf("Session %s: output released with %s noise, spent epsilon %v and delta %v", share.Session, sess.privacy.Mechanism, epsilon, delta)
+	}
 
 	return &pb.Ack{Message: "Out received"}, nil
 }
 
+// GetAddedOut waits until every other participant has sent its out share to
+// req.Participant and returns their sum.
 func (s *server) GetAddedOut(ctx context.Context, req *pb.GetAddedOutRequest) (*pb.GetAddedOutResponse, error) {
+	if err := s.checkOwner(req.Participant); err != nil {
+		return nil, err
+	}
+	if err := s.checkParticipant(req.Session, req.Participant); err != nil {
+		return nil, err
+	}
+
+	var totalAddedOut []*big.Int
+	var received, expected int
+	var scheme pb.Scheme
+	err := s.waitUntil(ctx, req.Session, func(sess *session) bool {
+		scheme = sess.scheme
+		totalAddedOut = sess.outShares[req.Participant]
+		received, expected = len(sess.outFrom[req.Participant]), sess.expected()
+		return scheme != pb.Scheme_ADDITIVE || received >= expected
+	})
+	if err == nil && scheme != pb.Scheme_ADDITIVE {
+		return nil, status.Errorf(codes.FailedPrecondition, "session %q uses %s sharing, use GetOutShares", req.Session, scheme)
+	}
+	if err != nil {
+		return nil, waitError(err, "out shares", req.Participant, received, expected)
+	}
+	log.Printf("Session %s: returning added out for %s: %d", req.Session, req.Participant, totalAddedOut)
+
+	return &pb.GetAddedOutResponse{AddedOut: s.field.Marshal(totalAddedOut)}, nil
+}
+
+// GetAddedShares waits until every other participant has sent its share to
+// req.Participant and returns their sum.
+func (s *server) GetAddedShares(ctx context.Context, req *pb.GetAddedSharesRequest) (*pb.GetAddedSharesResponse, error) {
+	if err := s.checkOwner(req.Participant); err != nil {
+		return nil, err
+	}
+	if err := s.checkParticipant(req.Session, req.Participant); err != nil {
+		return nil, err
+	}
+
+	var totalAddedShares []*big.Int
+	var received, expected int
+	err := s.waitUntil(ctx, req.Session, func(sess *session) bool {
+		totalAddedShares = sess.receivedShares[req.Participant]
+		received, expected = len(sess.shareFrom[req.Participant]), sess.expected()
+		return received >= expected
+	})
+	if err != nil {
+		return nil, waitError(err, "shares", req.Participant, received, expected)
+	}
+	log.Printf("Session %s: returning added shares for %s: %d", req.Session, req.Participant, totalAddedShares)
+
+	return &pb.GetAddedSharesResponse{AddedShares: s.field.Marshal(totalAddedShares)}, nil
+}
+
+// GetOutShares waits until at least req.Minimum out shares were sent to
+// req.Participant and returns them one by one. With Shamir sharing this lets a
+// participant reconstruct as soon as a threshold of its peers answered, even
+// if others dropped out.
+func (s *server) GetOutShares(ctx context.Context, req *pb.GetOutSharesRequest) (*pb.GetOutSharesResponse, error) {
+	if err := s.checkOwner(req.Participant); err != nil {
+		return nil, err
+	}
+	if err := s.checkParticipant(req.Session, req.Participant); err != nil {
+		return nil, err
+	}
+
+	minimum := int(req.Minimum)
+	s.mu.RLock()
+	sess, err := s.lookupSession(req.Session)
+	if err == nil && (minimum < 1 || minimum > sess.expected()) {
+		err = status.Errorf(codes.InvalidArgument, "minimum %d out of range [1, %d]", minimum, sess.expected())
+	}
+	s.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var shares []*pb.ShareOut
+	var received int
+	err = s.waitUntil(ctx, req.Session, func(sess *session) bool {
+		received = len(sess.outFrom[req.Participant])
+		if received < minimum {
+			return false
+		}
+		shares = shares[:0]
+		for _, from := range sess.participants {
+			if data, ok := sess.outFrom[req.Participant][from]; ok {
+				shares = append(shares, &pb.ShareOut{From: from, To: req.Participant, Data: s.field.Marshal(data), Session: req.Session})
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, waitError(err, "out shares", req.Participant, received, minimum)
+	}
+	log.Printf("Session %s: returning %d out shares for %s", req.Session, len(shares), req.Participant)
+
+	return &pb.GetOutSharesResponse{Shares: shares}, nil
+}
+
+// waitError adds how far the round got to a timed out wait.
+func waitError(err error, what, participant string, received, expected int) error {
+	if code := status.Code(err); code == codes.DeadlineExceeded || code == codes.Canceled {
+		return status.Errorf(code, "only %d of %d %s for %s arrived", received, expected, what, participant)
+	}
+	return err
+}
+
+// CreateSession registers a new session. Creating a session again with the
+// same id and participants is a no-op, so every party may call it. A session
+// with noise is charged to a participant's budget on the dataset once they
+// take part, by creating or joining it themselves or by sending their first
+// share, whether or not its output is ever released. It is refused once a
+// listed participant's budget would be exceeded. Sessions without noise on a
+// named dataset are always refused. A caller may have up to maxOpenSessions
+// sessions open that it created and has not left, and a participant's endpoint in
+// peer-to-peer mode only takes part in sessions listing its owner.
+func (s *server) CreateSession(ctx context.Context, req *pb.CreateSessionRequest) (*pb.CreateSessionResponse, error) {
+	if len(req.Participants) < 2 {
+		return nil, status.Errorf(codes.InvalidArgument, "need at least 2 participants, got %d", len(req.Participants))
+	}
+	seen := make(map[string]bool)
+	for _, p := range req.Participants {
+		if p == "" || seen[p] {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid or duplicate participant %q", p)
+		}
+		seen[p] = true
+	}
+	if s.owner != "" && !seen[s.owner] {
+		return nil, status.Errorf(codes.InvalidArgument, "session does not include %q, the owner of this endpoint", s.owner)
+	}
+	switch req.Scheme {
+	case pb.Scheme_ADDITIVE:
+		if req.Threshold != 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "additive sharing takes no threshold")
+		}
+	case pb.Scheme_SHAMIR:
+		// A threshold of 1 would make every share the input itself
+		if req.Threshold < 2 || int(req.Threshold) > len(req.Participants) {
+			return nil, status.Errorf(codes.InvalidArgument, "threshold %d out of range [2, %d]", req.Threshold, len(req.Participants))
+		}
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown scheme %v", req.Scheme)
+	}
+	if req.Length < 0 || req.Length > maxLength {
+		return nil, status.Errorf(codes.InvalidArgument, "vector length %d out of range [0, %d]", req.Length, maxLength)
+	}
+	privacy, err := privacyParams(req.Privacy)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkBudget(privacy); err != nil {
+		return nil, err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	totalAddedOut := s.outShares[req.Participant]
-	log.Printf("Returning added out for %s: %d", req.Participant, totalAddedOut)
+	id := req.Session
+	if id == "" {
+		if id, err = newSessionID(); err != nil {
+			return nil, status.Errorf(codes.Internal, "could not generate session id: %v", err)
+		}
+	}
+	if existing, ok := s.sessions[id]; ok {
+		if !existing.sameSetup(req, privacy) {
+			return nil, status.Errorf(codes.AlreadyExists, "session %q already exists with another setup", id)
+		}
+		if err := s.callerTakesPart(ctx, id, existing); err != nil {
+			return nil, err
+		}
+		return &pb.CreateSessionResponse{Session: id}, nil
+	}
+	creator := ""
+	if names, err := callerIdentities(ctx); err == nil && len(names) > 0 {
+		creator = names[0]
+		if s.openSessions(creator) >= maxOpenSessions {
+			return nil, status.Errorf(codes.ResourceExhausted, "%s already has %d open sessions, close some first", creator, maxOpenSessions)
+		}
+	}
+	if err := s.checkLedger(req.Participants, req.Dataset, privacy); err != nil {
+		return nil, err
+	}
+	instance, err := newSessionID()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not generate session instance: %v", err)
+	}
 
-	return &pb.GetAddedOutResponse{AddedOut: totalAddedOut}, nil
+	err = s.record(storage.Record{
+		Kind: storage.KindCreate, Session: id, Instance: instance, From: creator, Participants: slices.Clone(req.Participants), Scheme: int32(req.Scheme), Threshold: int(req.Threshold), Length: vectorLength(req.Length),
+		Mechanism: string(privacy.Mechanism), Epsilon: privacy.Epsilon, Delta: privacy.Delta, Sensitivity: privacy.Sensitivity, Dataset: req.Dataset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Session %s: created %s session for %v", id, req.Scheme, req.Participants)
+	if err := s.callerTakesPart(ctx, id, s.sessions[id]); err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateSessionResponse{Session: id}, nil
 }
 
-func (s *server) GetAddedShares(ctx context.Context, req *pb.GetAddedSharesRequest) (*pb.GetAddedSharesResponse, error) {
+// CloseSession drops the state of a session. A participant closing it only
+// leaves it, so the others can still fetch their outputs, and the state is
+// dropped once every participant left. Anyone else allowed to close it, like
+// an admin, drops it right away. The records of dropped sessions are
+// compacted away in batches, and only the results of the last maxPublished
+// sessions are kept, so the store of a long-running server does not grow
+// without bound.
+func (s *server) CloseSession(ctx context.Context, req *pb.CloseSessionRequest) (*pb.Ack, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	totalAddedShares := s.receivedShares[req.Participant]
-	log.Printf("Returning added shares for %s: %d", req.Participant, totalAddedShares)
+	sess, err := s.lookupSession(req.Session)
+	if err != nil {
+		return nil, err
+	}
+	rec := storage.Record{Kind: storage.KindClose, Session: req.Session}
+	if names, err := callerIdentities(ctx); err == nil {
+		for _, name := range names {
+			if sess.hasParticipant(name) {
+				rec.From = name
+				break
+			}
+		}
+	}
+	if rec.From != "" && sess.left[rec.From] {
+		return &pb.Ack{Message: "Session already left"}, nil
+	}
+	if err := s.record(rec); err != nil {
+		return nil, err
+	}
+	if _, open := s.sessions[req.Session]; open {
+		log.Printf("Session %s: %s left, %d of %d participants remain", req.Session, rec.From, len(sess.participants)-len(sess.left), len(sess.participants))
+		return &pb.Ack{Message: "Session left"}, nil
+	}
+	log.Printf("Session %s: closed", req.Session)
+	// The close is durable already, a failed compaction is retried with the
+	// next one
+	if s.dead >= s.compactAfter {
+		if err := s.compact(); err != nil {
+			log.Printf("Could not compact the state store: %v", err)
+		}
+	}
+
+	return &pb.Ack{Message: "Session closed"}, nil
+}
+
+// elements decodes field elements received from a participant.
+func (s *server) elements(bs [][]byte) ([]*big.Int, error) {
+	values, err := s.field.Unmarshal(bs)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid field element: %v", err)
+	}
+	return values, nil
+}
 
-	return &pb.GetAddedSharesResponse{AddedShares: totalAddedShares}, nil
+// checkOwner makes sure a peer-to-peer endpoint only deals with its owner's
+// shares.
+func (s *server) checkOwner(participant string) error {
+	if s.owner != "" && participant != s.owner {
+		return status.Errorf(codes.PermissionDenied, "this endpoint only holds the shares of %s", s.owner)
+	}
+	return nil
 }
 
-func loadTLSCredentials() (credentials.TransportCredentials, error) {
-	serverCert, err := tls.LoadX509KeyPair("cert/server-cert.pem", "cert/server-key.pem")
+// PublishAggregate records the final result a participant of a session
+// computed. In peer-to-peer mode this is the only thing the central server
+// learns besides the setup of the session.
+func (s *server) PublishAggregate(ctx context.Context, agg *pb.Aggregate) (*pb.Ack, error) {
+	values, err := s.elements(agg.Values)
 	if err != nil {
-		log.Printf("Error loading server certificate and key: %v", err)
 		return nil, err
 	}
 
-	config := &tls.Config{
-		Certificates: []tls.Certificate{serverCert},
-		ClientAuth:   tls.NoClientCert,
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, err := s.lookupSession(agg.Session)
+	if err != nil {
+		return nil, err
+	}
+	if !sess.hasParticipant(agg.From) {
+		return nil, status.Errorf(codes.InvalidArgument, "%q is not a participant of this session", agg.From)
+	}
+	if err := sess.checkLength(agg.From, len(values)); err != nil {
+		return nil, err
+	}
+	if err := s.record(storage.Record{Kind: storage.KindPublish, Session: agg.Session, From: agg.From, Values: values}); err != nil {
+		return nil, err
+	}
+	log.Printf("Session %s: %s published aggregate %d", agg.Session, agg.From, values)
+
+	return &pb.Ack{Message: "Aggregate published"}, nil
+}
+
+// GetAggregate returns the results published for a session, sorted by
+// participant, so its participants can check that they agree. Results are
+// kept for the last maxPublished sessions that published.
+func (s *server) GetAggregate(ctx context.Context, req *pb.GetAggregateRequest) (*pb.GetAggregateResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results, ok := s.published[req.Session]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "nothing published for session %q", req.Session)
+	}
+
+	froms := make([]string, 0, len(results))
+	for from := range results {
+		froms = append(froms, from)
 	}
+	slices.Sort(froms)
 
-	return credentials.NewTLS(config), nil
+	response := &pb.GetAggregateResponse{}
+	for _, from := range froms {
+		response.Results = append(response.Results, &pb.Aggregate{Session: req.Session, From: from, Values: s.field.Marshal(results[from])})
+	}
+	return response, nil
 }
 
-func StartServer() {
-	s := &server{
-		receivedShares: make(map[string]int64),
-		outShares:      make(map[string]int64),
+func loadTLSCredentials(cfg *config.Config, certName string) (credentials.TransportCredentials, *pki.Reloader, error) {
+	// Client certificates must be signed by our CA. The server certificate
+	// and the CA are reloaded when the files change on disk.
+	certFile, keyFile := cfg.TLS.KeyPair(certName)
+	reloader, err := pki.NewReloader(certFile, keyFile, cfg.TLS.CAFile)
+	if err != nil {
+		log.Printf("Error loading server certificate and key: %v", err)
+		return nil, nil, err
+	}
+
+	return credentials.NewTLS(reloader.ServerConfig()), reloader, nil
+}
+
+// Server is a running aggregation server or peer-to-peer endpoint.
+type Server struct {
+	cfg      *config.Config
+	svc      *server
+	grpc     *grpc.Server
+	reloader *pki.Reloader
+
+	stopOnce sync.Once
+	stopped  chan struct{} // closed once Stop has finished
+	stopErr  error
+}
+
+// New sets up the central aggregation server and restores the sessions of a
+// previous run from its store.
+func New(cfg *config.Config) (*Server, error) {
+	return newServer(cfg, "", "server")
+}
+
+// NewPeer sets up cfg.Identity's own endpoint for peer-to-peer mode. Peers
+// deliver their shares for cfg.Identity straight to it, and it presents the
+// participant's certificate.
+func NewPeer(cfg *config.Config) (*Server, error) {
+	return newServer(cfg, cfg.Identity, cfg.Identity)
+}
+
+func newServer(cfg *config.Config, owner, certName string) (*Server, error) {
+	s, err := newService(cfg, owner)
+	if err != nil {
+		return nil, err
 	}
 
-	tlsCredentials, err := loadTLSCredentials()
+	tlsCredentials, reloader, err := loadTLSCredentials(cfg, certName)
 	if err != nil {
-		log.Fatal("cannot load TLS credentials: ", err)
+		s.close()
+		return nil, fmt.Errorf("cannot load TLS credentials: %w", err)
 	}
 
 	grpcServer := grpc.NewServer(
 		grpc.Creds(tlsCredentials),
+		grpc.UnaryInterceptor(s.authInterceptor),
+		// Parties ping idle connections to find dead ones
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{MinTime: config.MinKeepalive, PermitWithoutStream: true}),
 	)
+	pb.RegisterSecretSharingServiceServer(grpcServer, s)
+
+	return &Server{
+		cfg:      cfg,
+		svc:      s,
+		grpc:     grpcServer,
+		reloader: reloader,
+		stopped:  make(chan struct{}),
+	}, nil
+}
+
+// newService sets up the service behind a server and restores its state.
+func newService(cfg *config.Config, owner string) (*server, error) {
+	s := &server{
+		sessions:  make(map[string]*session),
+		field:     cfg.Field(),
+		owner:     owner,
+		published: make(map[string]map[string][]*big.Int),
 
-	lis, err := net.Listen("tcp", ":50051")
+		compactAfter: compactAfter,
+		waitTimeout:  cfg.Timeouts.Wait,
+		privacy:      cfg.Privacy,
+		ledger:       make(map[budgetKey]spending),
+	}
+
+	policy, err := LoadPolicy(cfg.PolicyFile)
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		return nil, fmt.Errorf("cannot load authorization policy: %w", err)
 	}
-	log.Println("Starting server")
+	s.policy = policy
 
-	pb.RegisterSecretSharingServiceServer(grpcServer, s)
+	if s.store, err = openStore(cfg); err != nil {
+		return nil, fmt.Errorf("cannot open state store: %w", err)
+	}
+	if s.ledgerStore, err = openLedger(cfg, owner, s.store); err != nil {
+		s.store.Close()
+		return nil, fmt.Errorf("cannot open privacy budget ledger: %w", err)
+	}
+	if err := s.restore(); err != nil {
+		s.close()
+		return nil, fmt.Errorf("cannot restore state: %w", err)
+	}
+	return s, nil
+}
+
+// Start listens on cfg.ListenAddr and serves until ctx is done or Stop is
+// called. When ctx is done the server stops gracefully within the configured
+// shutdown timeout. Start returns once the server has stopped.
+func (s *Server) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lis, err := net.Listen("tcp", s.cfg.ListenAddr)
+	if err != nil {
+		s.svc.close()
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	if s.svc.owner != "" {
+		log.Printf("Starting endpoint of %s on %s", s.svc.owner, s.cfg.ListenAddr)
+	} else {
+		log.Printf("Starting server on %s", s.cfg.ListenAddr)
+	}
+
+	go s.reloader.Watch(ctx, s.cfg.Timeouts.CertReload)
+	go func() {
+		<-ctx.Done()
+		stopCtx, cancel := context.WithTimeout(context.Background(), s.cfg.Timeouts.Shutdown)
+		defer cancel()
+		s.Stop(stopCtx)
+	}()
 
 	log.Println("Server started")
-	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+	serveErr := s.grpc.Serve(lis)
+	// If Serve failed on its own, this makes the goroutine above stop the
+	// server and close the store
+	cancel()
+	<-s.stopped
+	if serveErr != nil {
+		return fmt.Errorf("failed to serve: %w", serveErr)
 	}
+	return s.stopErr
+}
+
+// Stop stops accepting connections and lets the calls in flight finish. Calls
+// still waiting for shares when ctx is done fail with Aborted; sessions in a
+// durable store resume when the server is started again. Finally the store is
+// closed, and the ledger with it. Stop may be called more than once.
+func (s *Server) Stop(ctx context.Context) error {
+	s.stopOnce.Do(func() {
+		defer close(s.stopped)
+		log.Println("Stopping server")
+
+		drained := make(chan struct{})
+		go func() {
+			s.grpc.GracefulStop()
+			close(drained)
+		}()
+		select {
+		case <-drained:
+		case <-ctx.Done():
+			// Only waits for shares can hold the drain up. Aborted, they
+			// return right away and the callers learn why.
+			s.svc.abortSessions()
+			<-drained
+		}
+
+		s.stopErr = s.svc.close()
+		log.Println("Server stopped")
+	})
+	<-s.stopped
+	return s.stopErr
 }
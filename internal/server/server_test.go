@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	pb "hospital/api"
+	"hospital/internal/config"
+	"hospital/internal/sharing"
+	"hospital/internal/storage"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// newTestServer returns a server without TLS or policy, restored from store.
+// It compacts the store whenever a session is dropped.
+func newTestServer(t *testing.T, store storage.Store, privacy config.PrivacyConfig) *server {
+	t.Helper()
+	return newTestServerWithLedger(t, store, store, privacy)
+}
+
+// newTestServerWithLedger is newTestServer keeping the ledger in its own
+// store, like the central server.
+func newTestServerWithLedger(t *testing.T, store, ledger storage.Store, privacy config.PrivacyConfig) *server {
+	t.Helper()
+	field, err := sharing.ParseField(sharing.DefaultModulus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &server{
+		sessions:    make(map[string]*session),
+		field:       field,
+		store:       store,
+		ledgerStore: ledger,
+		published:   make(map[string]map[string][]*big.Int),
+		waitTimeout: time.Second,
+		privacy:     privacy,
+		ledger:      make(map[budgetKey]spending),
+	}
+	if err := s.restore(); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// as returns a context of a call made with a verified certificate issued for
+// name, or without peer information if name is empty.
+func as(name string) context.Context {
+	ctx := context.Background()
+	if name == "" {
+		return ctx
+	}
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: name}}
+	state := tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	return peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+}
+
+// createSession creates a session with laplace noise of epsilon, or without
+// noise when epsilon is 0, on behalf of no participant.
+func createSession(s *server, id, dataset string, epsilon float64, participants ...string) error {
+	return createSessionAs(s, "", id, dataset, epsilon, participants...)
+}
+
+// createSessionAs is createSession called by caller.
+func createSessionAs(s *server, caller, id, dataset string, epsilon float64, participants ...string) error {
+	req := &pb.CreateSessionRequest{Session: id, Participants: participants, Dataset: dataset}
+	if epsilon > 0 {
+		req.Privacy = &pb.Privacy{Mechanism: pb.Mechanism_LAPLACE, Epsilon: epsilon, Sensitivity: 1}
+	}
+	_, err := s.CreateSession(as(caller), req)
+	return err
+}
+
+// wantCode fails the test unless err carries the gRPC status code want.
+func wantCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Fatalf("got %v (%v), want %v", got, err, want)
+	}
+}
+
+func TestCreateSessionThreshold(t *testing.T) {
+	s := newTestServer(t, storage.NewMemory(), config.PrivacyConfig{})
+	participants := []string{"Alice", "Bob", "Charlie"}
+	for _, tt := range []struct {
+		threshold int32
+		want      codes.Code
+	}{{0, codes.InvalidArgument}, {1, codes.InvalidArgument}, {2, codes.OK}, {3, codes.OK}, {4, codes.InvalidArgument}} {
+		req := &pb.CreateSessionRequest{Participants: participants, Scheme: pb.Scheme_SHAMIR, Threshold: tt.threshold}
+		_, err := s.CreateSession(context.Background(), req)
+		if got := status.Code(err); got != tt.want {
+			t.Errorf("threshold %d: got %v (%v), want %v", tt.threshold, got, err, tt.want)
+		}
+	}
+}
+
+func TestCreateSessionLimitsOpenSessions(t *testing.T) {
+	store := storage.NewMemory()
+	s := newTestServer(t, store, config.PrivacyConfig{})
+	for i := range maxOpenSessions {
+		if err := createSessionAs(s, "Alice", fmt.Sprint("s", i), "", 0, "Alice", "Bob"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wantCode(t, createSessionAs(s, "Alice", "one-more", "", 0, "Alice", "Bob"), codes.ResourceExhausted)
+
+	// Others have their own limit, joining is no creating, and closing a
+	// session makes room again, also after a restart
+	if err := createSessionAs(s, "Bob", "bob", "", 0, "Alice", "Bob"); err != nil {
+		t.Fatal(err)
+	}
+	if err := createSessionAs(s, "Alice", "bob", "", 0, "Alice", "Bob"); err != nil {
+		t.Fatalf("joining Bob's session: %v", err)
+	}
+	s = newTestServer(t, store, config.PrivacyConfig{})
+	wantCode(t, createSessionAs(s, "Alice", "one-more", "", 0, "Alice", "Bob"), codes.ResourceExhausted)
+	if _, err := s.CloseSession(context.Background(), &pb.CloseSessionRequest{Session: "s0"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := createSessionAs(s, "Alice", "one-more", "", 0, "Alice", "Bob"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateSessionLimitCountsOnlySessionsNotLeft(t *testing.T) {
+	s := newTestServer(t, storage.NewMemory(), config.PrivacyConfig{})
+	for i := range maxOpenSessions {
+		id := fmt.Sprint("s", i)
+		if err := createSessionAs(s, "Alice", id, "", 0, "Alice", "Bob"); err != nil {
+			t.Fatal(err)
+		}
+		if err := createSessionAs(s, "Bob", id, "", 0, "Alice", "Bob"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Bob never closes the sessions, which stay open for him, but Alice left
+	// them and may create others
+	for i := range maxOpenSessions {
+		if _, err := s.CloseSession(as("Alice"), &pb.CloseSessionRequest{Session: fmt.Sprint("s", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := createSessionAs(s, "Alice", "with-charlie", "", 0, "Alice", "Charlie"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEndpointRefusesSessionsWithoutOwner(t *testing.T) {
+	s := newTestServer(t, storage.NewMemory(), config.PrivacyConfig{})
+	s.owner = "Alice"
+	wantCode(t, createSession(s, "others", "", 0, "Bob", "Charlie"), codes.InvalidArgument)
+	if err := createSession(s, "own", "", 0, "Alice", "Bob"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,263 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"math/big"
+	"slices"
+
+	pb "hospital/api"
+	"hospital/internal/dp"
+	"hospital/internal/storage"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// session holds the aggregation state of a single round, so separate runs
+// against the same server never add onto each other's totals.
+type session struct {
+	participants   []string
+	creator        string // caller that created the session, empty for admins without a certificate
+	instance       string // random, tells the session apart from others with the same id
+	scheme         pb.Scheme
+	threshold      int
+	length         int                   // elements of every input vector
+	privacy        dp.Params             // noise the participants add to the output
+	dataset        string                // dataset whose privacy budget the session spends
+	released       bool                  // set once the first out share arrived, spending the privacy budget
+	charged        map[string]bool       // participants the session was charged to, see takePart
+	left           map[string]bool       // participants that closed the session, it is dropped once all did
+	receivedShares map[string][]*big.Int // key is the participant and the value is the element-wise sum of its parts
+	outShares      map[string][]*big.Int
+	shareFrom      map[string]map[string]bool       // senders whose share was added into receivedShares
+	outFrom        map[string]map[string][]*big.Int // out shares per recipient, keyed by sender
+	triples        map[string][]*big.Int            // shares of a, b and c of every dealt Beaver triple, per participant
+	masks          map[string][]*big.Int            // shares of every dealt bit mask and its bits, per participant
+	openings       map[string]*opening              // keyed by round
+	delivered      map[messageID]delivery           // messages already applied, to drop retried duplicates
+	changed        chan struct{}                    // closed and replaced whenever the state changes
+	records        int                              // records of the session in the store, see server.dead
+	aborted        bool                             // set when the server shuts down before the round finished
+}
+
+// messageID identifies a message within a session. Retries of a message
+// carry the same id, so applying each id once gives exactly-once aggregation.
+type messageID struct {
+	from string
+	seq  uint64
+}
+
+// delivery is what a delivered message carried, to tell retries from other
+// messages reusing their id.
+type delivery struct {
+	to     string
+	kind   storage.Kind
+	digest [sha256.Size]byte // of the round and values
+}
+
+// delivery summarizes the message rec records.
+func (s *server) delivery(rec storage.Record) delivery {
+	h := sha256.New()
+	h.Write([]byte(rec.Round))
+	h.Write([]byte{0})
+	for _, v := range rec.Values {
+		h.Write(s.field.Bytes(v))
+	}
+	d := delivery{to: rec.To, kind: rec.Kind}
+	h.Sum(d.digest[:0])
+	return d
+}
+
+func newSession(participants []string, scheme pb.Scheme, threshold, length int, privacy dp.Params, dataset string) *session {
+	return &session{
+		participants:   participants,
+		scheme:         scheme,
+		threshold:      threshold,
+		length:         length,
+		privacy:        privacy,
+		dataset:        dataset,
+		receivedShares: make(map[string][]*big.Int),
+		outShares:      make(map[string][]*big.Int),
+		shareFrom:      make(map[string]map[string]bool),
+		outFrom:        make(map[string]map[string][]*big.Int),
+		triples:        make(map[string][]*big.Int),
+		masks:          make(map[string][]*big.Int),
+		openings:       make(map[string]*opening),
+		delivered:      make(map[messageID]delivery),
+		charged:        make(map[string]bool),
+		left:           make(map[string]bool),
+		changed:        make(chan struct{}),
+	}
+}
+
+// sameSetup reports whether a CreateSession request with the given privacy
+// parameters describes this session.
+func (sess *session) sameSetup(req *pb.CreateSessionRequest, privacy dp.Params) bool {
+	return slices.Equal(sess.participants, req.Participants) &&
+		sess.scheme == req.Scheme &&
+		sess.threshold == int(req.Threshold) &&
+		sess.length == vectorLength(req.Length) &&
+		sess.privacy == privacy &&
+		sess.dataset == req.Dataset
+}
+
+// vectorLength returns the number of elements a session with the requested
+// length shares; 0 means a single value.
+func vectorLength(requested int32) int {
+	return max(int(requested), 1)
+}
+
+// expected is the number of contributions every participant receives in each
+// phase: one from every other participant.
+func (sess *session) expected() int {
+	return len(sess.participants) - 1
+}
+
+// notify wakes up everyone waiting on the session. Callers must hold s.mu.
+func (sess *session) notify() {
+	close(sess.changed)
+	sess.changed = make(chan struct{})
+}
+
+func (sess *session) hasParticipant(name string) bool {
+	return slices.Contains(sess.participants, name)
+}
+
+// checkPair makes sure both ends of a message belong to the session.
+func (sess *session) checkPair(from, to string) error {
+	if !sess.hasParticipant(from) {
+		return status.Errorf(codes.InvalidArgument, "%q is not a participant of this session", from)
+	}
+	if !sess.hasParticipant(to) {
+		return status.Errorf(codes.InvalidArgument, "%q is not a participant of this session", to)
+	}
+	if from == to {
+		return status.Errorf(codes.InvalidArgument, "%q cannot send to itself", from)
+	}
+	return nil
+}
+
+// checkMessage validates the message rec is about to record and reports
+// whether it was already delivered before. A message reusing the id of a
+// delivered one with another recipient, kind or payload is not a retry but
+// a sender that lost track of its sequence numbers, e.g. a party that was
+// restarted into a running session, and is refused.
+func (s *server) checkMessage(sess *session, rec storage.Record) (bool, error) {
+	if rec.Seq == 0 {
+		return false, status.Errorf(codes.InvalidArgument, "message from %s has no sequence number", rec.From)
+	}
+	if delivered, ok := sess.delivered[messageID{rec.From, rec.Seq}]; ok {
+		if delivered != s.delivery(rec) {
+			return false, status.Errorf(codes.FailedPrecondition, "message %d from %s differs from the one delivered with the same sequence number", rec.Seq, rec.From)
+		}
+		return true, nil
+	}
+	return false, sess.checkPair(rec.From, rec.To)
+}
+
+// checkLength makes sure a share or out share has one element per element of
+// the session's vectors.
+func (sess *session) checkLength(from string, elements int) error {
+	if elements != sess.length {
+		return status.Errorf(codes.InvalidArgument, "message from %s has %d elements, the session shares %d", from, elements, sess.length)
+	}
+	return nil
+}
+
+// openSessions returns the number of open sessions creator created and has
+// not left yet. Sessions it left only stay open for participants that did not,
+// which must not keep it from creating new ones. Callers must hold s.mu.
+func (s *server) openSessions(creator string) int {
+	var n int
+	for _, sess := range s.sessions {
+		if sess.creator == creator && !sess.left[creator] {
+			n++
+		}
+	}
+	return n
+}
+
+// lookupSession returns the session with the given id. Callers must hold s.mu.
+func (s *server) lookupSession(id string) (*session, error) {
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown session %q", id)
+	}
+	return sess, nil
+}
+
+// checkParticipant makes sure name takes part in the session with the given id.
+func (s *server) checkParticipant(id, name string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sess, err := s.lookupSession(id)
+	if err != nil {
+		return err
+	}
+	if !sess.hasParticipant(name) {
+		return status.Errorf(codes.InvalidArgument, "%q is not a participant of this session", name)
+	}
+	return nil
+}
+
+// waitUntil blocks until done reports true for the session, the session is
+// closed or aborted, or the wait deadline passes. done is called with s.mu
+// read-locked.
+func (s *server) waitUntil(ctx context.Context, id string, done func(*session) bool) error {
+	ctx, cancel := context.WithTimeout(ctx, s.waitTimeout)
+	defer cancel()
+
+	for {
+		s.mu.RLock()
+		sess, err := s.lookupSession(id)
+		if err != nil {
+			s.mu.RUnlock()
+			return err
+		}
+		if done(sess) {
+			s.mu.RUnlock()
+			return nil
+		}
+		if sess.aborted {
+			s.mu.RUnlock()
+			return status.Errorf(codes.Aborted, "session %q aborted, the server is shutting down", id)
+		}
+		changed := sess.changed
+		s.mu.RUnlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// abortSessions wakes up everyone still waiting on a session with an error,
+// so the server can shut down without waiting for shares that cannot arrive
+// anymore.
+func (s *server) abortSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.sessions) > 0 {
+		log.Printf("Aborting %d unfinished sessions", len(s.sessions))
+	}
+	for _, sess := range s.sessions {
+		sess.aborted = true
+		sess.notify()
+	}
+}
+
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	pb "hospital/api"
+	"hospital/internal/config"
+	"hospital/internal/storage"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestDuplicateMessages(t *testing.T) {
+	store := storage.NewMemory()
+	s := newTestServer(t, store, config.PrivacyConfig{})
+	if err := createSession(s, "s1", "", 0, "Alice", "Bob", "Charlie"); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	share := func(to string, x int64) *pb.Share {
+		return &pb.Share{Session: "s1", From: "Alice", To: to, Seq: 1, Parts: [][]byte{s.field.Bytes(s.field.Int(x))}}
+	}
+
+	if _, err := s.SendShare(ctx, share("Bob", 7)); err != nil {
+		t.Fatal(err)
+	}
+	for _, restart := range []bool{false, true} {
+		if restart {
+			s = newTestServer(t, store, config.PrivacyConfig{})
+		}
+		// A retry is acknowledged without being added twice
+		ack, err := s.SendShare(ctx, share("Bob", 7))
+		if err != nil {
+			t.Fatalf("retry: %v", err)
+		}
+		if ack.Message != "Share already received" {
+			t.Errorf("retry acknowledged with %q", ack.Message)
+		}
+		if got := s.sessions["s1"].receivedShares["Bob"][0].Int64(); got != 7 {
+			t.Errorf("Bob received %d after a retry, want 7", got)
+		}
+
+		// Anything else reusing the sequence number is refused
+		wantCode(t, func() error { _, err := s.SendShare(ctx, share("Bob", 8)); return err }(), codes.FailedPrecondition)
+		wantCode(t, func() error { _, err := s.SendShare(ctx, share("Charlie", 7)); return err }(), codes.FailedPrecondition)
+		out := &pb.ShareOut{Session: "s1", From: "Alice", To: "Bob", Seq: 1, Data: share("Bob", 7).Parts}
+		wantCode(t, func() error { _, err := s.SendShareOut(ctx, out); return err }(), codes.FailedPrecondition)
+	}
+}
+
+func TestCloseSession(t *testing.T) {
+	store := storage.NewMemory()
+	s := newTestServer(t, store, config.PrivacyConfig{})
+	closeAs := func(caller string) string {
+		t.Helper()
+		ack, err := s.CloseSession(as(caller), &pb.CloseSessionRequest{Session: "s1"})
+		if err != nil {
+			t.Fatalf("closing as %q: %v", caller, err)
+		}
+		return ack.Message
+	}
+	open := func() bool {
+		_, ok := s.sessions["s1"]
+		return ok
+	}
+
+	if err := createSession(s, "s1", "", 0, "Alice", "Bob", "Charlie"); err != nil {
+		t.Fatal(err)
+	}
+	// Participants only leave, the others can still fetch their outputs
+	closeAs("Alice")
+	if got := closeAs("Alice"); got != "Session already left" {
+		t.Errorf("leaving twice acknowledged with %q", got)
+	}
+	s = newTestServer(t, store, config.PrivacyConfig{})
+	closeAs("Bob")
+	if !open() {
+		t.Fatal("session dropped before every participant left")
+	}
+	if got := closeAs("Charlie"); got != "Session closed" || open() {
+		t.Errorf("session still open after everyone left: %q", got)
+	}
+
+	// Once dropped the id can be used again, and anyone else closes it for
+	// everyone
+	if err := createSession(s, "s1", "", 0, "Alice", "Bob", "Charlie"); err != nil {
+		t.Fatalf("reusing the id: %v", err)
+	}
+	if got := closeAs(""); got != "Session closed" || open() {
+		t.Errorf("admin close acknowledged with %q", got)
+	}
+}
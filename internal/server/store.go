@@ -0,0 +1,263 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"slices"
+
+	pb "hospital/api"
+	"hospital/internal/config"
+	"hospital/internal/dp"
+	"hospital/internal/storage"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// openStore returns the store configured for the server: a write-ahead log
+// when a state file is set, memory otherwise.
+func openStore(cfg *config.Config) (storage.Store, error) {
+	if cfg.StateFile == "" {
+		return storage.NewMemory(), nil
+	}
+	return storage.OpenWAL(cfg.StateFile)
+}
+
+// openLedger returns the store of the privacy budget ledger. The central
+// server keeps the ledger in its own file, so budgets do not reset on a
+// restart even when the sessions are kept in memory only. Peer endpoints keep
+// it with their sessions.
+func openLedger(cfg *config.Config, owner string, sessions storage.Store) (storage.Store, error) {
+	if owner != "" {
+		return sessions, nil
+	}
+	if cfg.Privacy.LedgerFile == "" {
+		return nil, errors.New("no ledger file configured, budgets would reset on every restart")
+	}
+	if cfg.Privacy.LedgerFile == cfg.StateFile {
+		return nil, fmt.Errorf("ledger file and state file are both %s", cfg.StateFile)
+	}
+	return storage.OpenWAL(cfg.Privacy.LedgerFile)
+}
+
+// close closes the stores of the server.
+func (s *server) close() error {
+	err := s.store.Close()
+	if s.ledgerStore != s.store {
+		err = errors.Join(err, s.ledgerStore.Close())
+	}
+	return err
+}
+
+// record stores a state change and then applies it. Callers must hold s.mu
+// and have validated rec, so the change is only lost if the server crashes
+// before it is acknowledged.
+func (s *server) record(rec storage.Record) error {
+	store := s.store
+	if rec.Kind == storage.KindSpend {
+		store = s.ledgerStore
+	}
+	if err := store.Append(rec); err != nil {
+		return status.Errorf(codes.Unavailable, "could not store %s record: %v", rec.Kind, err)
+	}
+	if err := s.apply(rec); err != nil {
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	return nil
+}
+
+// apply changes the in-memory state as described by rec. It is used both for
+// new requests and to replay the store on startup. Callers must hold s.mu.
+func (s *server) apply(rec storage.Record) error {
+	if rec.Kind == storage.KindCreate {
+		privacy := dp.Params{Mechanism: dp.Mechanism(rec.Mechanism), Epsilon: rec.Epsilon, Delta: rec.Delta, Sensitivity: rec.Sensitivity}
+		sess := newSession(rec.Participants, pb.Scheme(rec.Scheme), rec.Threshold, rec.Length, privacy, rec.Dataset)
+		sess.records = 1
+		sess.creator = rec.From
+		sess.instance = rec.Instance
+		s.sessions[rec.Session] = sess
+		return nil
+	}
+	if rec.Kind == storage.KindSpend {
+		s.charge(rec.Participants, rec.Dataset, rec.Epsilon, rec.Delta)
+		// The ledger is replayed after the sessions, so the spending of a
+		// closed session can meet a new one reusing its id
+		if sess, ok := s.sessions[rec.Session]; ok && sess.instance == rec.Instance {
+			for _, p := range rec.Participants {
+				sess.charged[p] = true
+			}
+		}
+		return nil
+	}
+	if rec.Kind == storage.KindPublish {
+		s.publish(rec.Session, rec.From, rec.Values)
+		return nil
+	}
+
+	sess, ok := s.sessions[rec.Session]
+	if !ok {
+		return fmt.Errorf("%s record for unknown session %q", rec.Kind, rec.Session)
+	}
+	sess.records++
+	switch rec.Kind {
+	case storage.KindShare:
+		sum, err := s.addTo(sess, sess.receivedShares[rec.To], rec.Values)
+		if err != nil {
+			return err
+		}
+		if sess.shareFrom[rec.To] == nil {
+			sess.shareFrom[rec.To] = make(map[string]bool)
+		}
+		sess.shareFrom[rec.To][rec.From] = true
+		sess.delivered[messageID{rec.From, rec.Seq}] = s.delivery(rec)
+		sess.receivedShares[rec.To] = sum
+	case storage.KindOut:
+		sum, err := s.addTo(sess, sess.outShares[rec.To], rec.Values)
+		if err != nil {
+			return err
+		}
+		if sess.outFrom[rec.To] == nil {
+			sess.outFrom[rec.To] = make(map[string][]*big.Int)
+		}
+		sess.outFrom[rec.To][rec.From] = rec.Values
+		sess.delivered[messageID{rec.From, rec.Seq}] = s.delivery(rec)
+		sess.outShares[rec.To] = sum
+		sess.released = true
+	case storage.KindTriples:
+		n := len(sess.participants)
+		if len(rec.Values)%(3*n) != 0 {
+			return fmt.Errorf("triples record with %d values for %d participants", len(rec.Values), n)
+		}
+		for i := 0; i < len(rec.Values); i += 3 * n {
+			for j, participant := range sess.participants {
+				sess.triples[participant] = append(sess.triples[participant], rec.Values[i+3*j:i+3*j+3]...)
+			}
+		}
+	case storage.KindMasks:
+		n, size := len(sess.participants), s.maskSize()
+		if len(rec.Values)%(n*size) != 0 {
+			return fmt.Errorf("masks record with %d values for %d participants", len(rec.Values), n)
+		}
+		for i := 0; i < len(rec.Values); i += n * size {
+			for j, participant := range sess.participants {
+				sess.masks[participant] = append(sess.masks[participant], rec.Values[i+j*size:i+(j+1)*size]...)
+			}
+		}
+	case storage.KindOpening:
+		round, ok := sess.openings[rec.Round]
+		if !ok {
+			round = newOpening(len(rec.Values))
+			sess.openings[rec.Round] = round
+		}
+		total := round.sums[rec.To]
+		if total == nil {
+			total = s.field.Zeros(round.length)
+		}
+		sum, err := s.field.AddVectors(total, rec.Values)
+		if err != nil {
+			return err
+		}
+		if round.from[rec.To] == nil {
+			round.from[rec.To] = make(map[string]bool)
+		}
+		round.from[rec.To][rec.From] = true
+		sess.delivered[messageID{rec.From, rec.Seq}] = s.delivery(rec)
+		round.sums[rec.To] = sum
+	case storage.KindClose:
+		if rec.From != "" {
+			sess.left[rec.From] = true
+			if len(sess.left) < len(sess.participants) {
+				break
+			}
+		}
+		delete(s.sessions, rec.Session)
+		s.dead += sess.records
+	default:
+		return fmt.Errorf("unknown record kind %q", rec.Kind)
+	}
+	sess.notify()
+	return nil
+}
+
+// publish records the result from published for a session. Once more than
+// maxPublished sessions published, the results of the oldest are dropped.
+// Replaying the store drops the same ones. Callers must hold s.mu.
+func (s *server) publish(id, from string, values []*big.Int) {
+	results, ok := s.published[id]
+	if !ok {
+		results = make(map[string][]*big.Int)
+		s.published[id] = results
+		s.publishedOrder = append(s.publishedOrder, id)
+	}
+	if _, ok := results[from]; ok {
+		s.dead++ // the result it replaces
+	}
+	results[from] = values
+
+	for len(s.publishedOrder) > maxPublished {
+		oldest := s.publishedOrder[0]
+		s.publishedOrder = s.publishedOrder[1:]
+		s.dead += len(s.published[oldest])
+		delete(s.published, oldest)
+	}
+}
+
+// addTo returns the element-wise sum of a running total and a new vector. The
+// sum is a new slice, so totals handed out earlier never change.
+func (s *server) addTo(sess *session, total, values []*big.Int) ([]*big.Int, error) {
+	if total == nil {
+		total = s.field.Zeros(sess.length)
+	}
+	return s.field.AddVectors(total, values)
+}
+
+// restore rebuilds the sessions and the privacy budget ledger from the store,
+// so rounds that were running when the server stopped continue where they
+// left off. The store is compacted afterwards.
+func (s *server) restore() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var records int
+	replay := func(rec storage.Record) error {
+		records++
+		return s.apply(rec)
+	}
+	if err := s.store.Replay(replay); err != nil {
+		return err
+	}
+	if s.ledgerStore != s.store {
+		if err := s.ledgerStore.Replay(replay); err != nil {
+			return err
+		}
+	}
+	if records > 0 {
+		log.Printf("Restored %d sessions from %d records", len(s.sessions), records)
+	}
+
+	return s.compact()
+}
+
+// compact drops the records of closed sessions from the store, except the
+// results they published that are still kept and, when the ledger shares the
+// store, what they spent. Callers must hold s.mu.
+func (s *server) compact() error {
+	err := s.store.Compact(func(rec storage.Record) bool {
+		switch rec.Kind {
+		case storage.KindPublish:
+			kept, ok := s.published[rec.Session][rec.From]
+			return ok && slices.EqualFunc(kept, rec.Values, func(a, b *big.Int) bool { return a.Cmp(b) == 0 })
+		case storage.KindSpend:
+			return true
+		}
+		_, open := s.sessions[rec.Session]
+		return open
+	})
+	if err != nil {
+		return err
+	}
+	s.dead = 0
+	return nil
+}
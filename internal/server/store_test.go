@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "hospital/api"
+	"hospital/internal/config"
+	"hospital/internal/storage"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCloseSessionCompactsStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.wal")
+	store, err := storage.OpenWAL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	size := func() int64 {
+		t.Helper()
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info.Size()
+	}
+
+	s := newTestServer(t, store, config.PrivacyConfig{})
+	for _, id := range []string{"s1", "s2"} {
+		if err := createSession(s, id, "", 0, "Alice", "Bob"); err != nil {
+			t.Fatal(err)
+		}
+		if err := sendShare(s, id, "Alice", "Bob"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before := size()
+
+	if _, err := s.CloseSession(context.Background(), &pb.CloseSessionRequest{Session: "s1"}); err != nil {
+		t.Fatal(err)
+	}
+	if after := size(); after >= before {
+		t.Fatalf("log has %d bytes after closing a session, %d before", after, before)
+	}
+
+	// The open session is intact after a restart, and closing it as well
+	// leaves nothing behind
+	s = newTestServer(t, store, config.PrivacyConfig{})
+	if got := s.sessions["s2"].receivedShares["Bob"]; len(got) != 1 || got[0].Int64() != 1 {
+		t.Fatalf("Bob received %v in s2 after compaction, want [1]", got)
+	}
+	for _, caller := range []string{"Alice", "Bob"} {
+		if _, err := s.CloseSession(as(caller), &pb.CloseSessionRequest{Session: "s2"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if after := size(); after != 0 {
+		t.Errorf("log has %d bytes after closing every session, want 0", after)
+	}
+}
+
+func TestCompactionIsBatched(t *testing.T) {
+	store := storage.NewMemory()
+	s := newTestServer(t, store, config.PrivacyConfig{})
+	s.compactAfter = 10
+	records := func() int {
+		t.Helper()
+		var n int
+		if err := store.Replay(func(storage.Record) error { n++; return nil }); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	// Two sessions of a create, a share and a close each stay in the store
+	// until there are ten dead records
+	for i := range 4 {
+		id := fmt.Sprint("s", i)
+		if err := createSession(s, id, "", 0, "Alice", "Bob"); err != nil {
+			t.Fatal(err)
+		}
+		if err := sendShare(s, id, "Alice", "Bob"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.CloseSession(context.Background(), &pb.CloseSessionRequest{Session: id}); err != nil {
+			t.Fatal(err)
+		}
+		want := 3 * (i + 1)
+		if i == 3 {
+			want = 0
+		}
+		if got := records(); got != want {
+			t.Fatalf("%d records after closing %d sessions, want %d", got, i+1, want)
+		}
+	}
+}
+
+func TestPublishedResultsAreBounded(t *testing.T) {
+	store := storage.NewMemory()
+	s := newTestServer(t, store, config.PrivacyConfig{})
+	s.mu.Lock()
+	for i := range maxPublished + 1 {
+		rec := storage.Record{Kind: storage.KindPublish, Session: fmt.Sprint("s", i), From: "Alice", Values: []*big.Int{big.NewInt(int64(i))}}
+		if err := s.record(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.mu.Unlock()
+
+	// The oldest results are dropped, and stay dropped after a restart
+	for range 2 {
+		if _, err := s.GetAggregate(context.Background(), &pb.GetAggregateRequest{Session: "s0"}); status.Code(err) != codes.NotFound {
+			t.Fatalf("results of the oldest session: got %v, want NotFound", err)
+		}
+		r, err := s.GetAggregate(context.Background(), &pb.GetAggregateRequest{Session: fmt.Sprint("s", maxPublished)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r.Results) != 1 {
+			t.Fatalf("newest session has %d results, want 1", len(r.Results))
+		}
+		s = newTestServer(t, store, config.PrivacyConfig{})
+	}
+}
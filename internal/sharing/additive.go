@@ -0,0 +1,38 @@
+// Package sharing implements secret sharing over a prime field.
+package sharing
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Share splits value into n additive shares that sum to value in the field.
+// The first n-1 shares are uniformly random, so any n-1 of them reveal
+// nothing about value.
+func (f *Field) Share(value *big.Int, n int) ([]*big.Int, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("cannot split into %d shares", n)
+	}
+
+	shares := make([]*big.Int, n)
+	last := f.Mod(value)
+	for i := 0; i < n-1; i++ {
+		r, err := f.Random()
+		if err != nil {
+			return nil, fmt.Errorf("could not draw random share: %w", err)
+		}
+		shares[i] = r
+		last = f.Sub(last, r)
+	}
+	shares[n-1] = last
+	return shares, nil
+}
+
+// Reconstruct adds the shares back together in the field.
+func (f *Field) Reconstruct(shares []*big.Int) *big.Int {
+	sum := new(big.Int)
+	for _, s := range shares {
+		sum = f.Add(sum, s)
+	}
+	return sum
+}
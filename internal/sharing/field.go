@@ -0,0 +1,178 @@
+package sharing
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"regexp"
+)
+
+// DefaultModulus is the Mersenne prime 2^127 - 1, large enough that sums of
+// fixed-point inputs never wrap and random shares cannot be guessed.
+const DefaultModulus = "2^127-1"
+
+// maxModulusBits bounds the modulus, so a configuration cannot make every
+// operation arbitrarily expensive.
+const maxModulusBits = 4096
+
+// powerForm matches moduli written like 2^127-1 or 2^255-19.
+var powerForm = regexp.MustCompile(`^2\^(\d+)\s*-\s*(\d+)$`)
+
+// ParseModulus reads a modulus written in decimal, in hex with a 0x prefix,
+// or as 2^k-c.
+func ParseModulus(s string) (*big.Int, error) {
+	if m := powerForm.FindStringSubmatch(s); m != nil {
+		var k, c big.Int
+		k.SetString(m[1], 10)
+		c.SetString(m[2], 10)
+		if !k.IsInt64() || k.Int64() > maxModulusBits {
+			return nil, fmt.Errorf("modulus %s larger than 2^%d", s, maxModulusBits)
+		}
+		p := new(big.Int).Lsh(big.NewInt(1), uint(k.Int64()))
+		return p.Sub(p, &c), nil
+	}
+	p, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid modulus %q", s)
+	}
+	return p, nil
+}
+
+// Field is the prime field the shares live in. Elements are *big.Int in
+// [0, modulus). Operations never modify their arguments and always return a
+// new value, so elements may be shared freely.
+type Field struct {
+	p    *big.Int
+	size int // bytes of an encoded element
+}
+
+// NewField checks that modulus is an odd prime and returns its field. The
+// secure comparison relies on the modulus being odd, and 2 leaves no room for
+// negative values anyway.
+func NewField(modulus *big.Int) (*Field, error) {
+	if modulus.Cmp(big.NewInt(3)) < 0 || modulus.BitLen() > maxModulusBits {
+		return nil, fmt.Errorf("modulus %v out of range [3, 2^%d]", modulus, maxModulusBits)
+	}
+	if !modulus.ProbablyPrime(20) {
+		return nil, fmt.Errorf("modulus %v is not prime", modulus)
+	}
+	return &Field{p: new(big.Int).Set(modulus), size: (modulus.BitLen() + 7) / 8}, nil
+}
+
+// ParseField is ParseModulus followed by NewField.
+func ParseField(s string) (*Field, error) {
+	p, err := ParseModulus(s)
+	if err != nil {
+		return nil, err
+	}
+	return NewField(p)
+}
+
+// Modulus returns a copy of the field's prime.
+func (f *Field) Modulus() *big.Int {
+	return new(big.Int).Set(f.p)
+}
+
+// Bits returns the bit length of the modulus, which every element fits in.
+func (f *Field) Bits() int {
+	return f.p.BitLen()
+}
+
+// Int returns x as a field element.
+func (f *Field) Int(x int64) *big.Int {
+	return f.Mod(big.NewInt(x))
+}
+
+// Mod reduces x into the range [0, modulus).
+func (f *Field) Mod(x *big.Int) *big.Int {
+	return new(big.Int).Mod(x, f.p)
+}
+
+// Add returns a + b mod modulus.
+func (f *Field) Add(a, b *big.Int) *big.Int {
+	z := new(big.Int).Add(a, b)
+	return z.Mod(z, f.p)
+}
+
+// Sub returns a - b mod modulus.
+func (f *Field) Sub(a, b *big.Int) *big.Int {
+	z := new(big.Int).Sub(a, b)
+	return z.Mod(z, f.p)
+}
+
+// Mul returns a * b mod modulus.
+func (f *Field) Mul(a, b *big.Int) *big.Int {
+	z := new(big.Int).Mul(a, b)
+	return z.Mod(z, f.p)
+}
+
+// Neg returns -a mod modulus.
+func (f *Field) Neg(a *big.Int) *big.Int {
+	z := new(big.Int).Neg(a)
+	return z.Mod(z, f.p)
+}
+
+// Inverse returns the multiplicative inverse of a mod the prime modulus.
+func (f *Field) Inverse(a *big.Int) (*big.Int, error) {
+	a = f.Mod(a)
+	if a.Sign() == 0 {
+		return nil, fmt.Errorf("0 has no inverse")
+	}
+	return a.ModInverse(a, f.p), nil
+}
+
+// Random draws a uniformly random field element from crypto/rand.
+func (f *Field) Random() (*big.Int, error) {
+	return rand.Int(rand.Reader, f.p)
+}
+
+// Signed maps a field element to [-(modulus-1)/2, (modulus-1)/2], reading
+// elements above modulus/2 as negative.
+func (f *Field) Signed(x *big.Int) *big.Int {
+	z := f.Mod(x)
+	if z.Cmp(new(big.Int).Rsh(f.p, 1)) > 0 {
+		z.Sub(z, f.p)
+	}
+	return z
+}
+
+// Bytes encodes x big-endian in a fixed number of bytes for the field.
+func (f *Field) Bytes(x *big.Int) []byte {
+	return f.Mod(x).FillBytes(make([]byte, f.size))
+}
+
+// FromBytes decodes an element encoded by Bytes. Values outside the field
+// are rejected rather than reduced, they mean sender and receiver disagree
+// on the modulus.
+func (f *Field) FromBytes(b []byte) (*big.Int, error) {
+	if len(b) != f.size {
+		return nil, fmt.Errorf("element has %d bytes, the field uses %d", len(b), f.size)
+	}
+	x := new(big.Int).SetBytes(b)
+	if x.Cmp(f.p) >= 0 {
+		return nil, fmt.Errorf("element out of the field")
+	}
+	return x, nil
+}
+
+// Marshal encodes every element of xs with Bytes.
+func (f *Field) Marshal(xs []*big.Int) [][]byte {
+	bs := make([][]byte, len(xs))
+	for i, x := range xs {
+		bs[i] = f.Bytes(x)
+	}
+	return bs
+}
+
+// Unmarshal decodes elements encoded by Marshal.
+func (f *Field) Unmarshal(bs [][]byte) ([]*big.Int, error) {
+	xs := make([]*big.Int, len(bs))
+	for i, b := range bs {
+		x, err := f.FromBytes(b)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		xs[i] = x
+	}
+	return xs, nil
+}
@@ -0,0 +1,119 @@
+package sharing
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseModulus(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"2^127-1", "170141183460469231731687303715884105727"},
+		{"2^255 - 19", "57896044618658097711785492504343953926634992332820282019728792003956564819949"},
+		{"0x7fffffff", "2147483647"},
+		{"2147483647", "2147483647"},
+	}
+	for _, tt := range tests {
+		p, err := ParseModulus(tt.in)
+		if err != nil {
+			t.Errorf("ParseModulus(%q): %v", tt.in, err)
+			continue
+		}
+		if p.String() != tt.want {
+			t.Errorf("ParseModulus(%q) = %v, want %s", tt.in, p, tt.want)
+		}
+	}
+	for _, in := range []string{"", "abc", "2^5000-1"} {
+		if _, err := ParseModulus(in); err == nil {
+			t.Errorf("ParseModulus(%q) succeeded", in)
+		}
+	}
+}
+
+func TestNewFieldRejects(t *testing.T) {
+	for _, p := range []int64{0, 1, 2, 4, 1 << 31} {
+		if _, err := NewField(big.NewInt(p)); err == nil {
+			t.Errorf("NewField(%d) succeeded", p)
+		}
+	}
+}
+
+func TestFieldArithmetic(t *testing.T) {
+	f, err := NewField(big.NewInt(101))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := f.Int(70), f.Int(-40)
+	if got := f.Add(a, b); got.Int64() != 30 {
+		t.Errorf("70 + -40 = %v", got)
+	}
+	if got := f.Sub(b, a); got.Int64() != 92 {
+		t.Errorf("-40 - 70 = %v, want 92", got)
+	}
+	if got := f.Mul(a, b); got.Int64() != 28 {
+		t.Errorf("70 * -40 = %v, want 28", got)
+	}
+	if got := f.Neg(a); got.Int64() != 31 {
+		t.Errorf("-70 = %v, want 31", got)
+	}
+	inv, err := f.Inverse(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Mul(a, inv); got.Int64() != 1 {
+		t.Errorf("70 * 70^-1 = %v", got)
+	}
+	if _, err := f.Inverse(f.Int(101)); err == nil {
+		t.Error("inverse of 0 succeeded")
+	}
+	if got := f.Signed(f.Int(-3)); got.Int64() != -3 {
+		t.Errorf("Signed(-3) = %v", got)
+	}
+	if got := f.Signed(f.Int(50)); got.Int64() != 50 {
+		t.Errorf("Signed(50) = %v", got)
+	}
+}
+
+func TestBytes(t *testing.T) {
+	f := mustField(t, DefaultModulus)
+	x, err := f.Random()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := f.Bytes(x)
+	if len(b) != 16 {
+		t.Fatalf("element encoded in %d bytes, want 16", len(b))
+	}
+	got, err := f.FromBytes(b)
+	if err != nil || got.Cmp(x) != 0 {
+		t.Fatalf("FromBytes(Bytes(%v)) = %v, %v", x, got, err)
+	}
+	if _, err := f.FromBytes(b[1:]); err == nil {
+		t.Error("short element accepted")
+	}
+	if _, err := f.FromBytes(f.Modulus().FillBytes(make([]byte, 16))); err == nil {
+		t.Error("modulus accepted as element")
+	}
+}
+
+func TestShareReconstruct(t *testing.T) {
+	f := mustField(t, DefaultModulus)
+	secret := f.Int(-42)
+	for n := 1; n <= 5; n++ {
+		shares, err := f.Share(secret, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(shares) != n {
+			t.Fatalf("Share into %d returned %d shares", n, len(shares))
+		}
+		if got := f.Reconstruct(shares); got.Cmp(secret) != 0 {
+			t.Errorf("Reconstruct of %d shares = %v, want %v", n, got, secret)
+		}
+	}
+	if _, err := f.Share(secret, 0); err == nil {
+		t.Error("Share into 0 succeeded")
+	}
+}
@@ -0,0 +1,99 @@
+package sharing
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// DefaultScale keeps six decimal places, enough for lab values like
+// 5.42 mmol/L.
+const DefaultScale int64 = 1_000_000
+
+// Encoding maps real numbers into the field as fixed-point values: x is
+// stored as round(x * Scale) mod the modulus. Negative numbers wrap around
+// like two's complement, so elements above modulus/2 decode as negative.
+// Sums of encoded values decode to the sum of the inputs as long as the sum
+// stays within ±Max.
+type Encoding struct {
+	Scale int64
+	Field *Field
+}
+
+// NewEncoding checks scale against the field and returns their encoding.
+func NewEncoding(scale int64, field *Field) (Encoding, error) {
+	if scale < 1 || big.NewInt(scale).Cmp(new(big.Int).Rsh(field.p, 1)) > 0 {
+		return Encoding{}, fmt.Errorf("scale %d out of range [1, modulus/2]", scale)
+	}
+	return Encoding{Scale: scale, Field: field}, nil
+}
+
+// Max is the largest magnitude the encoding represents.
+func (e Encoding) Max() float64 {
+	limit, _ := new(big.Float).SetInt(new(big.Int).Rsh(e.Field.p, 1)).Float64()
+	return limit / float64(e.Scale)
+}
+
+// Encode rounds x to the nearest multiple of 1/Scale and returns its field
+// element.
+func (e Encoding) Encode(x float64) (*big.Int, error) {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return nil, fmt.Errorf("cannot encode %v", x)
+	}
+	// x * Scale overflows float64 long before it leaves the largest fields
+	rounded := math.Round(x * float64(e.Scale))
+	if math.IsInf(rounded, 0) {
+		return nil, e.rangeError(x)
+	}
+	scaled, _ := big.NewFloat(rounded).Int(nil)
+	if scaled.CmpAbs(new(big.Int).Rsh(e.Field.p, 1)) > 0 {
+		return nil, e.rangeError(x)
+	}
+	return e.Field.Mod(scaled), nil
+}
+
+func (e Encoding) rangeError(x float64) error {
+	return fmt.Errorf("%v out of range ±%v for scale %d", x, e.Max(), e.Scale)
+}
+
+// Decode returns the real number a field element stands for.
+func (e Encoding) Decode(v *big.Int) float64 {
+	x := new(big.Float).SetInt(e.Field.Signed(v))
+	x.Quo(x, new(big.Float).SetInt64(e.Scale))
+	f, _ := x.Float64()
+	return f
+}
+
+// ShareFloat encodes x and splits it into n additive shares.
+func (e Encoding) ShareFloat(x float64, n int) ([]*big.Int, error) {
+	v, err := e.Encode(x)
+	if err != nil {
+		return nil, err
+	}
+	return e.Field.Share(v, n)
+}
+
+// ReconstructFloat adds the shares back together and decodes the result.
+func (e Encoding) ReconstructFloat(shares []*big.Int) float64 {
+	return e.Decode(e.Field.Reconstruct(shares))
+}
+
+// ShamirShareFloat encodes x and splits it into n Shamir shares with
+// threshold t.
+func (e Encoding) ShamirShareFloat(x float64, t, n int) ([]Point, error) {
+	v, err := e.Encode(x)
+	if err != nil {
+		return nil, err
+	}
+	return e.Field.ShamirShare(v, t, n)
+}
+
+// ShamirReconstructFloat interpolates the secret from the points and decodes
+// it.
+func (e Encoding) ShamirReconstructFloat(points []Point) (float64, error) {
+	v, err := e.Field.ShamirReconstruct(points)
+	if err != nil {
+		return 0, err
+	}
+	return e.Decode(v), nil
+}
@@ -0,0 +1,92 @@
+package sharing
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	// 2^31-1 with scale 1 makes Max exact
+	small, err := NewField(big.NewInt(1<<31 - 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	smallEnc := Encoding{Scale: 1, Field: small}
+	defaultEnc := Encoding{Scale: DefaultScale, Field: mustField(t, DefaultModulus)}
+
+	tests := []struct {
+		name string
+		enc  Encoding
+		x    float64
+		want float64 // decoded value, unless wantErr
+		err  string
+	}{
+		{"zero", defaultEnc, 0, 0, ""},
+		{"positive", defaultEnc, 5.42, 5.42, ""},
+		{"negative", defaultEnc, -1.5, -1.5, ""},
+		{"rounded", defaultEnc, 1.0000004, 1, ""},
+		{"max", smallEnc, smallEnc.Max(), 1<<30 - 1, ""},
+		{"min", smallEnc, -smallEnc.Max(), -(1<<30 - 1), ""},
+		{"above max", smallEnc, smallEnc.Max() + 1, 0, "out of range"},
+		{"below min", smallEnc, -smallEnc.Max() - 1, 0, "out of range"},
+		{"above default max", defaultEnc, 1e32, 0, "out of range"},
+		{"scale overflow", defaultEnc, 1e305, 0, "out of range"},
+		{"negative scale overflow", defaultEnc, -1e305, 0, "out of range"},
+		{"largest float", defaultEnc, math.MaxFloat64, 0, "out of range"},
+		{"infinity", defaultEnc, math.Inf(1), 0, "cannot encode"},
+		{"nan", defaultEnc, math.NaN(), 0, "cannot encode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := tt.enc.Encode(tt.x)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Encode(%v) = %v, %v; want error containing %q", tt.x, v, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Encode(%v): %v", tt.x, err)
+			}
+			if got := tt.enc.Decode(v); got != tt.want {
+				t.Errorf("Decode(Encode(%v)) = %v, want %v", tt.x, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconstructFloat(t *testing.T) {
+	enc := Encoding{Scale: DefaultScale, Field: mustField(t, DefaultModulus)}
+	inputs := []float64{5.42, -300.25, 0.000001}
+	var sum []*big.Int
+	for _, x := range inputs {
+		shares, err := enc.ShareFloat(x, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := enc.ReconstructFloat(shares); math.Abs(got-x) > 1e-9 {
+			t.Errorf("ReconstructFloat(ShareFloat(%v)) = %v", x, got)
+		}
+		if sum == nil {
+			sum = shares
+			continue
+		}
+		if sum, err = enc.Field.AddVectors(sum, shares); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := enc.ReconstructFloat(sum), -294.829999; math.Abs(got-want) > 1e-9 {
+		t.Errorf("sum of shares reconstructs to %v, want %v", got, want)
+	}
+}
+
+func mustField(t *testing.T, modulus string) *Field {
+	t.Helper()
+	f, err := ParseField(modulus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
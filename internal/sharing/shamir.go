@@ -0,0 +1,87 @@
+package sharing
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Point is one Shamir share: the sharing polynomial evaluated at X.
+type Point struct {
+	X int64
+	Y *big.Int
+}
+
+// ShamirShare splits value into n shares such that any t of them
+// reconstruct it and fewer than t reveal nothing. Share i is the evaluation
+// of a random degree t-1 polynomial with constant term value at X = i+1.
+// The threshold is at least 2: with t = 1 every share is value itself.
+func (f *Field) ShamirShare(value *big.Int, t, n int) ([]Point, error) {
+	if t < 2 || t > n {
+		return nil, fmt.Errorf("threshold %d out of range [2, %d]", t, n)
+	}
+	if big.NewInt(int64(n)).Cmp(f.p) >= 0 {
+		return nil, fmt.Errorf("modulus %v too small for %d shares", f.p, n)
+	}
+
+	coefficients := make([]*big.Int, t)
+	coefficients[0] = f.Mod(value)
+	for i := 1; i < t; i++ {
+		c, err := f.Random()
+		if err != nil {
+			return nil, fmt.Errorf("could not draw random coefficient: %w", err)
+		}
+		coefficients[i] = c
+	}
+
+	points := make([]Point, n)
+	for i := range points {
+		x := int64(i + 1)
+		points[i] = Point{X: x, Y: f.evaluate(coefficients, f.Int(x))}
+	}
+	return points, nil
+}
+
+// evaluate computes the polynomial at x with Horner's rule.
+func (f *Field) evaluate(coefficients []*big.Int, x *big.Int) *big.Int {
+	y := new(big.Int)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y = f.Add(f.Mul(y, x), coefficients[i])
+	}
+	return y
+}
+
+// ShamirReconstruct interpolates the polynomial through points and returns
+// its value at 0. It needs at least t points with distinct, non-zero X.
+func (f *Field) ShamirReconstruct(points []Point) (*big.Int, error) {
+	if len(points) == 0 {
+		return nil, fmt.Errorf("no points to reconstruct from")
+	}
+	seen := make(map[string]bool)
+	for _, p := range points {
+		x := f.Int(p.X)
+		if x.Sign() == 0 || seen[x.String()] {
+			return nil, fmt.Errorf("invalid or duplicate x coordinate %d", p.X)
+		}
+		seen[x.String()] = true
+	}
+
+	secret := new(big.Int)
+	for i, pi := range points {
+		// Lagrange basis polynomial for point i evaluated at 0:
+		// prod over j != i of x_j / (x_j - x_i)
+		num, den := big.NewInt(1), big.NewInt(1)
+		for j, pj := range points {
+			if i == j {
+				continue
+			}
+			num = f.Mul(num, f.Int(pj.X))
+			den = f.Mul(den, f.Sub(f.Int(pj.X), f.Int(pi.X)))
+		}
+		inv, err := f.Inverse(den)
+		if err != nil {
+			return nil, err
+		}
+		secret = f.Add(secret, f.Mul(pi.Y, f.Mul(num, inv)))
+	}
+	return secret, nil
+}
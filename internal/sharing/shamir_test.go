@@ -0,0 +1,91 @@
+package sharing
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestShamirReconstruct(t *testing.T) {
+	f := mustField(t, DefaultModulus)
+	secret := f.Int(-1234)
+	const threshold, n = 3, 5
+
+	points, err := f.ShamirShare(secret, threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every subset of threshold points, like the parties left after dropouts
+	for a := 0; a < n; a++ {
+		for b := a + 1; b < n; b++ {
+			for c := b + 1; c < n; c++ {
+				got, err := f.ShamirReconstruct([]Point{points[c], points[a], points[b]})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Cmp(secret) != 0 {
+					t.Errorf("points %d, %d, %d reconstruct %v, want %v", a, b, c, got, secret)
+				}
+			}
+		}
+	}
+	got, err := f.ShamirReconstruct(points)
+	if err != nil || got.Cmp(secret) != 0 {
+		t.Errorf("all points reconstruct %v, %v; want %v", got, err, secret)
+	}
+	// Too few points interpolate a lower degree polynomial
+	if got, err := f.ShamirReconstruct(points[:threshold-1]); err == nil && got.Cmp(secret) == 0 {
+		t.Errorf("%d points reconstructed the secret", threshold-1)
+	}
+}
+
+func TestShamirShareThreshold(t *testing.T) {
+	f := mustField(t, DefaultModulus)
+	for _, tt := range []struct{ t, n int }{{0, 3}, {1, 3}, {4, 3}} {
+		if _, err := f.ShamirShare(big.NewInt(1), tt.t, tt.n); err == nil {
+			t.Errorf("ShamirShare with threshold %d of %d succeeded", tt.t, tt.n)
+		}
+	}
+	// No share may be the secret itself, which a threshold of 1 would give
+	secret := big.NewInt(42)
+	points, err := f.ShamirShare(secret, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range points {
+		if p.Y.Cmp(secret) == 0 {
+			t.Errorf("share at x=%d equals the secret", p.X)
+		}
+	}
+}
+
+func TestShamirReconstructInvalid(t *testing.T) {
+	f := mustField(t, DefaultModulus)
+	one := big.NewInt(1)
+	for _, points := range [][]Point{
+		nil,
+		{{X: 0, Y: one}, {X: 1, Y: one}},
+		{{X: 1, Y: one}, {X: 1, Y: one}},
+	} {
+		if _, err := f.ShamirReconstruct(points); err == nil {
+			t.Errorf("ShamirReconstruct(%v) succeeded", points)
+		}
+	}
+}
+
+func TestShamirVector(t *testing.T) {
+	f := mustField(t, DefaultModulus)
+	values := []*big.Int{f.Int(5), f.Int(-7), f.Int(0)}
+	points, err := f.ShamirShareVector(values, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := f.ShamirReconstructVector(points[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range values {
+		if got[i].Cmp(values[i]) != 0 {
+			t.Errorf("element %d reconstructs %v, want %v", i, got[i], values[i])
+		}
+	}
+}
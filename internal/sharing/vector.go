@@ -0,0 +1,134 @@
+package sharing
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// VectorPoint is one Shamir share of a vector: every element's sharing
+// polynomial evaluated at the same X.
+type VectorPoint struct {
+	X int64
+	Y []*big.Int
+}
+
+// ShareVector splits every element of values into n additive shares. Share i
+// holds the i-th share of each element, in order.
+func (f *Field) ShareVector(values []*big.Int, n int) ([][]*big.Int, error) {
+	shares := make([][]*big.Int, n)
+	for i := range shares {
+		shares[i] = make([]*big.Int, len(values))
+	}
+	for j, v := range values {
+		parts, err := f.Share(v, n)
+		if err != nil {
+			return nil, err
+		}
+		for i, part := range parts {
+			shares[i][j] = part
+		}
+	}
+	return shares, nil
+}
+
+// ReconstructVector adds the shares back together element-wise.
+func (f *Field) ReconstructVector(shares [][]*big.Int) ([]*big.Int, error) {
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("no shares to reconstruct from")
+	}
+	sum := f.Zeros(len(shares[0]))
+	for _, share := range shares {
+		var err error
+		if sum, err = f.AddVectors(sum, share); err != nil {
+			return nil, err
+		}
+	}
+	return sum, nil
+}
+
+// Zeros returns a vector of n zero elements.
+func (f *Field) Zeros(n int) []*big.Int {
+	zeros := make([]*big.Int, n)
+	for i := range zeros {
+		zeros[i] = new(big.Int)
+	}
+	return zeros
+}
+
+// AddVectors returns the element-wise sum of a and b in a new slice.
+func (f *Field) AddVectors(a, b []*big.Int) ([]*big.Int, error) {
+	if len(a) != len(b) {
+		return nil, fmt.Errorf("cannot add vectors of length %d and %d", len(a), len(b))
+	}
+	sum := make([]*big.Int, len(a))
+	for i := range a {
+		sum[i] = f.Add(a[i], b[i])
+	}
+	return sum, nil
+}
+
+// ShamirShareVector splits every element of values into n Shamir shares with
+// threshold t, each with its own random polynomial. Share i is evaluated at
+// X = i+1.
+func (f *Field) ShamirShareVector(values []*big.Int, t, n int) ([]VectorPoint, error) {
+	points := make([]VectorPoint, n)
+	for i := range points {
+		points[i] = VectorPoint{X: int64(i + 1), Y: make([]*big.Int, len(values))}
+	}
+	for j, v := range values {
+		elementPoints, err := f.ShamirShare(v, t, n)
+		if err != nil {
+			return nil, err
+		}
+		for i, pt := range elementPoints {
+			points[i].Y[j] = pt.Y
+		}
+	}
+	return points, nil
+}
+
+// ShamirReconstructVector interpolates every element from the points, which
+// must all have the same length.
+func (f *Field) ShamirReconstructVector(points []VectorPoint) ([]*big.Int, error) {
+	if len(points) == 0 {
+		return nil, fmt.Errorf("no points to interpolate")
+	}
+	values := make([]*big.Int, len(points[0].Y))
+	elementPoints := make([]Point, len(points))
+	for j := range values {
+		for i, pt := range points {
+			if len(pt.Y) != len(values) {
+				return nil, fmt.Errorf("point at x=%d has %d elements, want %d", pt.X, len(pt.Y), len(values))
+			}
+			elementPoints[i] = Point{X: pt.X, Y: pt.Y[j]}
+		}
+		v, err := f.ShamirReconstruct(elementPoints)
+		if err != nil {
+			return nil, err
+		}
+		values[j] = v
+	}
+	return values, nil
+}
+
+// EncodeVector encodes every element of xs.
+func (e Encoding) EncodeVector(xs []float64) ([]*big.Int, error) {
+	values := make([]*big.Int, len(xs))
+	for i, x := range xs {
+		v, err := e.Encode(x)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// DecodeVector decodes every element of values.
+func (e Encoding) DecodeVector(values []*big.Int) []float64 {
+	xs := make([]float64, len(values))
+	for i, v := range values {
+		xs[i] = e.Decode(v)
+	}
+	return xs
+}
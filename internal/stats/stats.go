@@ -0,0 +1,124 @@
+// Package stats computes federated statistics over the hospitals' local rows
+// with the secure aggregation protocol. Every function runs one aggregation
+// round on a party's session, so each hospital calls the same function with
+// its own rows, and only the aggregate statistic is revealed.
+package stats
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"hospital/internal/client"
+)
+
+// MeanLength is the vector length of sessions used for Mean: the count and the
+// sum of the rows.
+const MeanLength = 2
+
+// Count returns the number of rows of all hospitals together. The session
+// must share single values.
+func Count(ctx context.Context, p *client.Party, rows []float64) (int64, error) {
+	return p.Contribute(ctx, int64(len(rows)))
+}
+
+// Sum returns the sum of the rows of all hospitals together. The session must
+// share single values.
+func Sum(ctx context.Context, p *client.Party, rows []float64) (float64, error) {
+	var sum float64
+	for _, x := range rows {
+		sum += x
+	}
+	return p.ContributeFloat(ctx, sum)
+}
+
+// Mean returns the mean of the rows of all hospitals together: the aggregated
+// sum divided by the aggregated count, both shared in the same round. The
+// session must share vectors of MeanLength elements. With differential
+// privacy noise the count can come out below one, even negative; Mean then
+// returns an error rather than dividing by it.
+//
+// The noise of the count and the sum is calibrated to a single sensitivity,
+// while one row changes the vector by 1 in the count and by its value in the
+// sum. The session's sensitivity must therefore be at least 1+max|row| over
+// all rows that may occur, or the privacy guarantee does not hold.
+func Mean(ctx context.Context, p *client.Party, rows []float64) (float64, error) {
+	var sum float64
+	for _, x := range rows {
+		sum += x
+	}
+	out, err := p.ContributeFloats(ctx, []float64{float64(len(rows)), sum})
+	if err != nil {
+		return 0, err
+	}
+	if out[0] < 1 {
+		return 0, fmt.Errorf("mean of %v rows", out[0])
+	}
+	return out[1] / out[0], nil
+}
+
+// Histogram returns how many rows of all hospitals together fall into each
+// bin. edges are the increasing bin boundaries: bin i holds the rows in
+// [edges[i], edges[i+1]), the last bin also its upper edge. Rows outside all
+// bins are not counted. The session must share vectors of len(edges)-1
+// elements.
+func Histogram(ctx context.Context, p *client.Party, rows, edges []float64) ([]int64, error) {
+	if err := checkEdges(edges); err != nil {
+		return nil, err
+	}
+	counts := make([]float64, len(edges)-1)
+	for _, x := range rows {
+		if i := bin(x, edges); i >= 0 {
+			counts[i]++
+		}
+	}
+
+	out, err := p.ContributeFloats(ctx, counts)
+	if err != nil {
+		return nil, err
+	}
+	histogram := make([]int64, len(out))
+	for i, c := range out {
+		histogram[i] = int64(math.Round(c))
+	}
+	return histogram, nil
+}
+
+// EqualWidth returns the edges of n bins of equal width between lo and hi.
+func EqualWidth(lo, hi float64, n int) ([]float64, error) {
+	if n < 1 || !(lo < hi) {
+		return nil, fmt.Errorf("cannot split [%v, %v] into %d bins", lo, hi, n)
+	}
+	edges := make([]float64, n+1)
+	for i := range edges {
+		edges[i] = lo + (hi-lo)*float64(i)/float64(n)
+	}
+	edges[n] = hi
+	return edges, nil
+}
+
+func checkEdges(edges []float64) error {
+	if len(edges) < 2 {
+		return fmt.Errorf("need at least 2 bin edges, got %d", len(edges))
+	}
+	for i := 1; i < len(edges); i++ {
+		if !(edges[i-1] < edges[i]) {
+			return fmt.Errorf("bin edges must increase, got %v after %v", edges[i], edges[i-1])
+		}
+	}
+	return nil
+}
+
+// bin returns the index of the bin x falls into, or -1 if none.
+func bin(x float64, edges []float64) int {
+	last := len(edges) - 1
+	if math.IsNaN(x) || x < edges[0] || x > edges[last] {
+		return -1
+	}
+	for i := 1; i < last; i++ {
+		if x < edges[i] {
+			return i - 1
+		}
+	}
+	return last - 1
+}
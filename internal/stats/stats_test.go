@@ -0,0 +1,138 @@
+package stats
+
+import (
+	"context"
+	"math"
+	"slices"
+	"testing"
+
+	"hospital/internal/client"
+	"hospital/internal/config"
+	"hospital/internal/testutil"
+)
+
+func TestEqualWidth(t *testing.T) {
+	edges, err := EqualWidth(0, 1, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []float64{0, 0.25, 0.5, 0.75, 1}; !slices.Equal(edges, want) {
+		t.Errorf("edges %v, want %v", edges, want)
+	}
+	// The last edge is hi exactly, whatever the rounding of the others
+	if edges, err := EqualWidth(0.1, 0.7, 3); err != nil || edges[3] != 0.7 {
+		t.Errorf("edges %v, %v, want 0.7 last", edges, err)
+	}
+	for _, c := range []struct {
+		lo, hi float64
+		n      int
+	}{{0, 1, 0}, {1, 1, 2}, {2, 1, 2}, {math.NaN(), 1, 2}} {
+		if _, err := EqualWidth(c.lo, c.hi, c.n); err == nil {
+			t.Errorf("EqualWidth(%v, %v, %d) succeeded", c.lo, c.hi, c.n)
+		}
+	}
+}
+
+func TestCheckEdges(t *testing.T) {
+	for _, c := range []struct {
+		edges []float64
+		ok    bool
+	}{
+		{[]float64{0, 1}, true},
+		{[]float64{-1, 0, 2.5}, true},
+		{nil, false},
+		{[]float64{0}, false},
+		{[]float64{0, 1, 1}, false},
+		{[]float64{1, 0}, false},
+		{[]float64{0, math.NaN()}, false},
+	} {
+		if err := checkEdges(c.edges); (err == nil) != c.ok {
+			t.Errorf("checkEdges(%v) = %v", c.edges, err)
+		}
+	}
+}
+
+func TestBin(t *testing.T) {
+	edges := []float64{0, 10, 20, 30}
+	for _, c := range []struct {
+		x    float64
+		want int
+	}{
+		{0, 0},
+		{9.99, 0},
+		{10, 1},
+		{25, 2},
+		{30, 2}, // the last bin holds its upper edge
+		{-0.1, -1},
+		{30.1, -1},
+		{math.NaN(), -1},
+		{math.Inf(1), -1},
+	} {
+		if got := bin(c.x, edges); got != c.want {
+			t.Errorf("bin(%v) = %d, want %d", c.x, got, c.want)
+		}
+	}
+}
+
+// run computes statistic over the rows of every participant in a session of
+// vectors of the given length and returns what each computed.
+func run(t *testing.T, cfg *config.Config, rows map[string][]float64, length int, statistic func(*client.Party, []float64) ([]float64, error)) (map[string][]float64, error) {
+	t.Helper()
+	var participants []client.Participant
+	for _, name := range []string{"Alice", "Bob", "Charlie"} {
+		participants = append(participants, client.Participant{Name: name, Inputs: rows[name]})
+	}
+	return client.RunParties(context.Background(), cfg, participants, client.Session{Length: length}, func(p *client.Party, pt client.Participant) ([]float64, error) {
+		return statistic(p, pt.Inputs)
+	})
+}
+
+func TestStatistics(t *testing.T) {
+	cfg := testutil.Config(t, "Alice", "Bob", "Charlie")
+	defer testutil.StartServer(t, cfg)()
+	ctx := context.Background()
+	rows := map[string][]float64{"Alice": {1, 2}, "Bob": {3}, "Charlie": {4, 5, 9}}
+
+	for _, c := range []struct {
+		name      string
+		length    int
+		statistic func(*client.Party, []float64) ([]float64, error)
+		want      []float64
+	}{
+		{"count", 1, func(p *client.Party, rows []float64) ([]float64, error) {
+			n, err := Count(ctx, p, rows)
+			return []float64{float64(n)}, err
+		}, []float64{6}},
+		{"mean", MeanLength, func(p *client.Party, rows []float64) ([]float64, error) {
+			mean, err := Mean(ctx, p, rows)
+			return []float64{mean}, err
+		}, []float64{4}},
+		{"histogram", 2, func(p *client.Party, rows []float64) ([]float64, error) {
+			counts, err := Histogram(ctx, p, rows, []float64{0, 3, 6})
+			out := make([]float64, len(counts))
+			for i, c := range counts {
+				out[i] = float64(c)
+			}
+			return out, err
+		}, []float64{2, 3}},
+	} {
+		outputs, err := run(t, cfg, rows, c.length, c.statistic)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		for name, out := range outputs {
+			if !slices.Equal(out, c.want) {
+				t.Errorf("%s: %s computed %v, want %v", c.name, name, out, c.want)
+			}
+		}
+	}
+
+	// No rows at all have no mean
+	_, err := run(t, cfg, nil, MeanLength, func(p *client.Party, rows []float64) ([]float64, error) {
+		mean, err := Mean(ctx, p, rows)
+		return []float64{mean}, err
+	})
+	if err == nil {
+		t.Error("mean of no rows succeeded")
+	}
+}
@@ -0,0 +1,62 @@
+package storage
+
+import "sync"
+
+// Memory keeps the records in memory only. It is the default when no state
+// file is configured, and loses everything when the process exits.
+type Memory struct {
+	mu      sync.Mutex
+	records []Record
+	closed  bool
+}
+
+// NewMemory returns an empty store.
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) Append(rec Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return ErrClosed
+	}
+	m.records = append(m.records, rec)
+	return nil
+}
+
+func (m *Memory) Replay(fn func(Record) error) error {
+	m.mu.Lock()
+	records := m.records[:len(m.records):len(m.records)]
+	m.mu.Unlock()
+
+	for _, rec := range records {
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Memory) Compact(keep func(Record) bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var kept []Record
+	for _, rec := range m.records {
+		if keep(rec) {
+			kept = append(kept, rec)
+		}
+	}
+	m.records = kept
+	return nil
+}
+
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closed = true
+	return nil
+}
@@ -0,0 +1,77 @@
+// Package storage records the state changes of the aggregation server, so the
+// server can rebuild its sessions after a restart.
+package storage
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrClosed is returned by a store that was already closed.
+var ErrClosed = errors.New("store closed")
+
+// Kind is the type of a state change.
+type Kind string
+
+// The state changes of the server.
+const (
+	KindCreate  Kind = "create"  // a session was created
+	KindShare   Kind = "share"   // a share arrived
+	KindOut     Kind = "out"     // an out share arrived
+	KindClose   Kind = "close"   // a session was closed, or left by a participant
+	KindPublish Kind = "publish" // a participant published its aggregate
+	KindTriples Kind = "triples" // the dealer handed out Beaver triples
+	KindOpening Kind = "opening" // a share of an opened value arrived
+	KindMasks   Kind = "masks"   // the dealer handed out random bit masks
+	KindSpend   Kind = "spend"   // a session was charged to the privacy budget ledger
+)
+
+// Record is a single state change. Which fields are set depends on Kind.
+type Record struct {
+	Kind    Kind   `json:"kind"`
+	Session string `json:"session"`
+
+	// KindCreate, and Participants, Dataset, Epsilon, Delta and Instance
+	// also KindSpend
+	Participants []string `json:"participants,omitempty"`
+	Scheme       int32    `json:"scheme,omitempty"`
+	Threshold    int      `json:"threshold,omitempty"`
+	Length       int      `json:"length,omitempty"`
+	Mechanism    string   `json:"mechanism,omitempty"` // differential privacy noise of the output
+	Epsilon      float64  `json:"epsilon,omitempty"`
+	Delta        float64  `json:"delta,omitempty"`
+	Sensitivity  float64  `json:"sensitivity,omitempty"`
+	Dataset      string   `json:"dataset,omitempty"` // dataset the session queries
+	// Instance tells sessions apart that reuse the id of a closed one, so
+	// spend records only mark the session they were charged to.
+	Instance string `json:"instance,omitempty"`
+
+	// KindShare, KindOut, KindPublish, KindTriples, KindOpening and
+	// KindMasks, and From also KindClose when a participant left the
+	// session rather than closing it for everyone and KindCreate when a
+	// participant created it. The values of KindTriples are, for every new
+	// triple in turn, each participant's shares of a, b and c. Those of
+	// KindMasks are, for every new mask in turn, each participant's share of
+	// the mask followed by its shares of the mask's bits.
+	From   string     `json:"from,omitempty"`
+	To     string     `json:"to,omitempty"`
+	Seq    uint64     `json:"seq,omitempty"`
+	Values []*big.Int `json:"values,omitempty"` // field elements
+	Round  string     `json:"round,omitempty"`  // KindOpening
+}
+
+// Store keeps the records of a server. Implementations are safe for
+// concurrent use.
+type Store interface {
+	// Append durably records rec. Once it returns nil, rec is replayed after
+	// a restart.
+	Append(rec Record) error
+	// Replay calls fn with every record in the order they were appended and
+	// stops at the first error.
+	Replay(fn func(Record) error) error
+	// Compact drops the records keep reports false for, e.g. those of
+	// closed sessions.
+	Compact(keep func(Record) bool) error
+	// Close releases the store. Records appended so far are kept.
+	Close() error
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var testRecords = []Record{
+	{Kind: KindCreate, Session: "s1", Participants: []string{"Alice", "Bob"}, Length: 1},
+	{Kind: KindShare, Session: "s1", From: "Alice", To: "Bob", Seq: 1, Values: []*big.Int{big.NewInt(7)}},
+	{Kind: KindCreate, Session: "s2", Participants: []string{"Alice", "Bob"}, Length: 1},
+	{Kind: KindClose, Session: "s1"},
+}
+
+func replayAll(t *testing.T, s Store) []Record {
+	t.Helper()
+	var recs []Record
+	if err := s.Replay(func(rec Record) error {
+		recs = append(recs, rec)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return recs
+}
+
+// testStore appends, compacts and closes s, which must be empty.
+func testStore(t *testing.T, s Store) {
+	for _, rec := range testRecords {
+		if err := s.Append(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := replayAll(t, s); !reflect.DeepEqual(got, testRecords) {
+		t.Fatalf("replayed %v, want %v", got, testRecords)
+	}
+
+	if err := s.Compact(func(rec Record) bool { return rec.Session == "s2" }); err != nil {
+		t.Fatal(err)
+	}
+	rec := Record{Kind: KindShare, Session: "s2", From: "Bob", To: "Alice", Seq: 1, Values: []*big.Int{big.NewInt(3)}}
+	if err := s.Append(rec); err != nil {
+		t.Fatalf("append after compaction: %v", err)
+	}
+	want := []Record{testRecords[2], rec}
+	if got := replayAll(t, s); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after compaction replayed %v, want %v", got, want)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Append(rec); !errors.Is(err, ErrClosed) {
+		t.Errorf("append after close: %v, want ErrClosed", err)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	testStore(t, NewMemory())
+}
+
+func TestWAL(t *testing.T) {
+	w, err := OpenWAL(filepath.Join(t.TempDir(), "state.wal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, w)
+}
+
+func TestWALReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.wal")
+	w, err := OpenWAL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range testRecords {
+		if err := w.Append(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Close()
+
+	if w, err = OpenWAL(path); err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if got := replayAll(t, w); !reflect.DeepEqual(got, testRecords) {
+		t.Fatalf("replayed %v after reopening, want %v", got, testRecords)
+	}
+}
+
+func TestWALTornWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.wal")
+	w, err := OpenWAL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if err := w.Append(testRecords[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	// A crash in the middle of the next write leaves half a line
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"kind":"share","sess`)
+	f.Close()
+
+	if got := replayAll(t, w); !reflect.DeepEqual(got, testRecords[:1]) {
+		t.Fatalf("replayed %v, want only the complete record", got)
+	}
+	if err := w.Append(testRecords[1]); err != nil {
+		t.Fatal(err)
+	}
+	if got := replayAll(t, w); !reflect.DeepEqual(got, testRecords[:2]) {
+		t.Fatalf("after the torn write was cut off replayed %v, want %v", got, testRecords[:2])
+	}
+}
+
+func TestWALCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.wal")
+	if err := os.WriteFile(path, []byte("not json\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	w, err := OpenWAL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if err := w.Replay(func(Record) error { return nil }); err == nil {
+		t.Error("replaying a corrupt log succeeded")
+	}
+}
@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// WAL is a write-ahead log on disk. Every record is written as one line of
+// JSON and synced before Append returns, so an acknowledged share survives a
+// crash of the server.
+type WAL struct {
+	path string
+
+	mu sync.Mutex
+	f  *os.File // nil once closed
+}
+
+// OpenWAL opens the log at path, creating it if it does not exist.
+func OpenWAL(path string) (*WAL, error) {
+	f, err := openLog(path)
+	if err != nil {
+		return nil, err
+	}
+	return &WAL{path: path, f: f}, nil
+}
+
+func openLog(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+}
+
+func (w *WAL) Append(rec Record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return ErrClosed
+	}
+	if _, err := w.f.Write(line); err != nil {
+		return fmt.Errorf("writing %s: %w", w.path, err)
+	}
+	if err := w.f.Sync(); err != nil {
+		return fmt.Errorf("syncing %s: %w", w.path, err)
+	}
+	return nil
+}
+
+// Replay reads the log from the start. A last line without a newline is the
+// remains of a write the server crashed in; it was never acknowledged, so it
+// is cut off rather than treated as corruption.
+func (w *WAL) Replay(fn func(Record) error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return ErrClosed
+	}
+	f, err := os.Open(w.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var offset int64
+	for lineNo := 1; ; lineNo++ {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) > 0 {
+				log.Printf("Dropping incomplete record at the end of %s", w.path)
+				return w.f.Truncate(offset)
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", w.path, err)
+		}
+		offset += int64(len(line))
+
+		var rec Record
+		if err := json.Unmarshal(bytes.TrimSpace(line), &rec); err != nil {
+			return fmt.Errorf("%s:%d: corrupt record: %w", w.path, lineNo, err)
+		}
+		if err := fn(rec); err != nil {
+			return fmt.Errorf("%s:%d: %w", w.path, lineNo, err)
+		}
+	}
+}
+
+// Compact rewrites the log with the records to keep. The new log is written
+// next to the old one and renamed over it, so a crash leaves either of them
+// intact.
+func (w *WAL) Compact(keep func(Record) bool) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return ErrClosed
+	}
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return err
+	}
+
+	var kept bytes.Buffer
+	for _, line := range bytes.SplitAfter(data, []byte{'\n'}) {
+		var rec Record
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return fmt.Errorf("%s: corrupt record: %w", w.path, err)
+		}
+		if keep(rec) {
+			kept.Write(line)
+		}
+	}
+
+	tmp := w.path + ".tmp"
+	if err := writeSynced(tmp, kept.Bytes()); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, w.path); err != nil {
+		return err
+	}
+	if err := syncDir(filepath.Dir(w.path)); err != nil {
+		return err
+	}
+
+	// Appends must go to the new file
+	f, err := openLog(w.path)
+	if err != nil {
+		return err
+	}
+	w.f.Close()
+	w.f = f
+	return nil
+}
+
+func (w *WAL) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return nil
+	}
+	err := errors.Join(w.f.Sync(), w.f.Close())
+	w.f = nil
+	return err
+}
+
+func writeSynced(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// syncDir makes a rename in dir durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,121 @@
+// Package testutil runs the aggregation server for the tests of the packages
+// built on top of it.
+package testutil
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"net"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"hospital/internal/config"
+	"hospital/internal/pki"
+	"hospital/internal/server"
+)
+
+// Config issues certificates for the server and participants into a
+// temporary directory and returns a configuration for a server with a state
+// file and a ledger file on a free port, authorizing calls with the
+// repository's default policy.
+func Config(t *testing.T, participants ...string) *config.Config {
+	t.Helper()
+	dir := t.TempDir()
+	ca, err := pki.NewCA(pkix.Name{CommonName: "Test CA"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ca.WriteCA(pki.Files(dir, "ca")); err != nil {
+		t.Fatal(err)
+	}
+	der, key, err := ca.IssueServer([]string{"localhost", "127.0.0.1"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile := pki.Files(dir, "server")
+	if err := pki.WriteKeyPair(certFile, keyFile, der, key); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range participants {
+		der, key, err := ca.IssueParticipant(name, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		certFile, keyFile := pki.Files(dir, name)
+		if err := pki.WriteKeyPair(certFile, keyFile, der, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	addr := FreeAddr(t)
+	cfg := config.Default()
+	cfg.ListenAddr, cfg.ServerAddr = addr, addr
+	cfg.PolicyFile = policyFile()
+	cfg.StateFile = filepath.Join(dir, "state.wal")
+	cfg.Privacy.LedgerFile = filepath.Join(dir, "ledger.wal")
+	cfg.TLS.CAFile, _ = pki.Files(dir, "ca")
+	cfg.TLS.CertDir = dir
+	cfg.Timeouts.Request = 2 * time.Second
+	cfg.Timeouts.Wait = 10 * time.Second
+	cfg.Timeouts.Shutdown = 100 * time.Millisecond
+	cfg.Retry = config.RetryConfig{InitialBackoff: 50 * time.Millisecond, MaxBackoff: 200 * time.Millisecond}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+// FreeAddr returns a local address nothing listens on.
+func FreeAddr(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	return lis.Addr().String()
+}
+
+// StartServer runs the central server until the returned function stops it.
+func StartServer(t *testing.T, cfg *config.Config) (stop func()) {
+	t.Helper()
+	srv, err := server.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return start(t, srv)
+}
+
+// StartEndpoint runs cfg.Identity's peer-to-peer endpoint until the returned
+// function stops it.
+func StartEndpoint(t *testing.T, cfg *config.Config) (stop func()) {
+	t.Helper()
+	srv, err := server.NewPeer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return start(t, srv)
+}
+
+func start(t *testing.T, srv *server.Server) (stop func()) {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- srv.Start(context.Background()) }()
+	return func() {
+		// Abort the waiting calls right away, like a crash would
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		srv.Stop(ctx)
+		if err := <-done; err != nil {
+			t.Errorf("server: %v", err)
+		}
+	}
+}
+
+// policyFile returns the path of config/policy.json, wherever the test runs.
+func policyFile() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "config", "policy.json")
+}